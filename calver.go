@@ -1,10 +1,14 @@
 package calver
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/snabb/isoweek"
@@ -14,6 +18,10 @@ const (
 	parsedDefaultYear  = 2000
 	parsedDefaultMonth = time.Month(1)
 	parsedDefaultDay   = 1
+
+	defaultTwoDigitYearStart = 2000
+	minYear                  = 1
+	maxYear                  = 9999
 )
 
 type Calver struct {
@@ -21,33 +29,55 @@ type Calver struct {
 	minor      int
 	micro      int
 	modifier   string
+	metadata   string
+	values     map[string]string // values of custom tokens
 	ts         time.Time
 	loc        *time.Location
 	layout     []token
 	trimSuffix bool
+	// lenient disables strict validation of calendar values when parsing
+	lenient bool
+	// twoDigitYearStart is the first year of the 100-year window of two-digit years. If 0, defaultTwoDigitYearStart is used.
+	twoDigitYearStart int
+	// compareModifier compares modifiers. If nil, modifiers are compared lexically.
+	compareModifier func(a, b string) int
+	// modifierKey returns the sort key of a modifier consistent with compareModifier. If nil, defaultModifierKey is used.
+	modifierKey func(m string) string
+	// modifierOrder identifies the ordering of compareModifier. Empty if modifiers are compared lexically.
+	modifierOrder string
+	// absentCal is the names of the calendar tokens in optional segments that were absent in the parsed version string.
+	absentCal []string
+	// clock provides the current time. If nil, the system clock is used.
+	clock Clock
 }
 
 type Calvers []*Calver
 
 var ErrNoVersions = errors.New("no versions")
 
-// New returns *Calver at the current time.
-func New(layout string) (*Calver, error) {
-	now := time.Now().UTC()
-	return NewWithTime(layout, now)
+// ErrCalendarYearWithISOWeek is reported by CheckLayout when a calendar year token is combined with an ISO week token.
+// The ISO week of the days around the new year may belong to the adjacent year, so ISO week tokens should be combined with ISO week-year tokens (GGGG, GG, 0G).
+var ErrCalendarYearWithISOWeek = fmt.Errorf("calendar year token (%v, %v, %v) is combined with ISO week token (%v, %v)", tYYYY, tYY, t0Y, tWW, t0W)
+
+// New returns *Calver at the current time of the clock (the system clock by default).
+// The location is UTC unless WithLocation is given.
+func New(layout string, opts ...Option) (*Calver, error) {
+	tokens, err := tokenizeLayout(layout)
+	if err != nil {
+		return nil, err
+	}
+	return newCalver(tokens, opts), nil
 }
 
 // NewWithTime returns *Calver at the given time.
-func NewWithTime(layout string, now time.Time) (*Calver, error) {
-	tokens, err := tokenizeLayout(layout)
+// The location is the location of now unless WithLocation is given.
+func NewWithTime(layout string, now time.Time, opts ...Option) (*Calver, error) {
+	cv, err := New(layout, slices.Concat([]Option{WithLocation(now.Location())}, opts)...)
 	if err != nil {
 		return nil, err
 	}
-	return &Calver{
-		ts:     now, // Do not initialize (zeronize) below hour for In()
-		loc:    now.Location(),
-		layout: tokens,
-	}, nil
+	cv.ts = now // Do not initialize (zeronize) below hour for In()
+	return cv, nil
 }
 
 // In sets *time.Location.
@@ -58,11 +88,310 @@ func (cv *Calver) In(loc *time.Location) *Calver {
 }
 
 // Parse version string using layout.
-func (cv *Calver) Parse(value string) (ncv *Calver, err error) {
+func (cv *Calver) Parse(value string) (*Calver, error) {
+	if hasOptional(cv.layout) {
+		return cv.parseOptional(value)
+	}
+	return cv.parse(value)
+}
+
+// Parse version string using layout at the current time.
+func Parse(layout, value string, opts ...Option) (*Calver, error) {
+	cv, err := New(layout, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return cv.Parse(value)
+}
+
+// String returns version string.
+func (cv *Calver) String() string {
+	if hasOptional(cv.layout) {
+		fcv := cv.clone()
+		fcv.layout = cv.resolveOptional(cv.layout)
+		return fcv.format()
+	}
+	return cv.format()
+}
+
+// Layout returns version layout.
+func (cv *Calver) Layout() string {
+	var s string
+	for _, t := range cv.layout {
+		s += t.token()
+	}
+	return s
+}
+
+// Next returns next version *Calver at the current time of the clock.
+func (cv *Calver) Next() (*Calver, error) {
+	return cv.NextWithTime(cv.now())
+}
+
+// Next returns next version *Calver at the given time.
+func (cv *Calver) NextWithTime(now time.Time) (ncv *Calver, err error) {
+	defer func() {
+		if ncv != nil {
+			ncv.modifier = "" // clear modifier
+			ncv.metadata = "" // clear metadata
+		}
+	}()
+	if cv.ts.After(now) {
+		return nil, fmt.Errorf("[%v] is older than the current setting (%v)", now.Truncate(0), cv.ts)
+	}
+	ncv = cv.clone()
+	ncv.ts = now
+	if cv.String() != ncv.String() {
+		// if the time version is different and time version is first in the layout, reset major/minor/micro version.
+		if IsTimeVersionFirst(ncv.layout) {
+			ncv.major = 0
+			ncv.minor = 0
+			ncv.micro = 0
+			ncv.resetCounters()
+		}
+		return ncv, nil
+	}
+	if ncv.modifier != "" {
+		// if the modifier is set, no need to bump up major/minor/micro version.
+		return ncv, nil
+	}
+	if contains(ncv.layout, tMICRO) {
+		return ncv.Micro()
+	}
+	if contains(ncv.layout, tMINOR) {
+		return ncv.Minor()
+	}
+	if contains(ncv.layout, tMAJOR) {
+		return ncv.Major()
+	}
+	if name, ok := ncv.lastCounter(); ok {
+		return ncv.Bump(name)
+	}
+	return nil, errors.New("failed to bump up version")
+}
+
+// Major returns next major version *Calver.
+func (cv *Calver) Major() (*Calver, error) {
+	if !contains(cv.layout, tMAJOR) {
+		return nil, fmt.Errorf("no 'MAJOR' in the layout '%s'", cv.Layout())
+	}
+	ncv := cv.clone()
+	ncv.major++
+	return ncv, nil
+}
+
+// Minor returns next minor version *Calver.
+func (cv *Calver) Minor() (*Calver, error) {
+	if !contains(cv.layout, tMINOR) {
+		return nil, fmt.Errorf("no 'MINOR' in the layout '%s'", cv.Layout())
+	}
+	ncv := cv.clone()
+	ncv.minor++
+	return ncv, nil
+}
+
+// Micro returns next micro version *Calver.
+func (cv *Calver) Micro() (*Calver, error) {
+	if !contains(cv.layout, tMICRO) {
+		return nil, fmt.Errorf("no 'MICRO' in the layout '%s'", cv.Layout())
+	}
+	ncv := cv.clone()
+	ncv.micro++
+	return ncv, nil
+}
+
+// WithMajor returns *Calver with major version.
+func (cv *Calver) WithMajor(n int) (*Calver, error) {
+	if !contains(cv.layout, tMAJOR) {
+		return nil, fmt.Errorf("no 'MAJOR' in the layout '%s'", cv.Layout())
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("invalid major version: %d", n)
+	}
+	ncv := cv.clone()
+	ncv.major = n
+	return ncv, nil
+}
+
+// WithMinor returns *Calver with minor version.
+func (cv *Calver) WithMinor(n int) (*Calver, error) {
+	if !contains(cv.layout, tMINOR) {
+		return nil, fmt.Errorf("no 'MINOR' in the layout '%s'", cv.Layout())
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("invalid minor version: %d", n)
+	}
+	ncv := cv.clone()
+	ncv.minor = n
+	return ncv, nil
+}
+
+// WithMicro returns *Calver with micro version.
+func (cv *Calver) WithMicro(n int) (*Calver, error) {
+	if !contains(cv.layout, tMICRO) {
+		return nil, fmt.Errorf("no 'MICRO' in the layout '%s'", cv.Layout())
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("invalid micro version: %d", n)
+	}
+	ncv := cv.clone()
+	ncv.micro = n
+	return ncv, nil
+}
+
+// WithTime returns *Calver with the time of the version.
+// The time is rendered in the location of the version (see In).
+// The time below the calendar tokens of the layout is initialized in the same way as Parse.
+func (cv *Calver) WithTime(t time.Time) (*Calver, error) {
+	cal := []token{}
+	for _, tt := range cv.layout {
+		tc, ok := tt.(tokenCal)
+		if !ok {
+			continue
+		}
+		// Keep the full year regardless of the two-digit year window
+		switch tc.token() {
+		case tYY.token(), t0Y.token():
+			tc = tYYYY
+		case tGG.token(), t0G.token():
+			tc = tGGGG
+		}
+		if len(cal) > 0 {
+			cal = append(cal, newTokenSep("."))
+		}
+		cal = append(cal, tc)
+	}
+	if len(cal) == 0 {
+		return nil, fmt.Errorf("no calendar token in the layout '%s'", cv.Layout())
+	}
+	ncv := cv.clone()
+	ncv.ts = t
+	pcv := cv.clone()
+	pcv.layout = cal
+	pcv.trimSuffix = false
+	p, err := pcv.parse(renderWith(ncv, cal))
+	if err != nil {
+		return nil, err
+	}
+	ncv.ts = p.ts
+	return ncv, nil
+}
+
+// Modifier returns *Calver with modifier.
+func (cv *Calver) Modifier(m string) (*Calver, error) {
+	if !contains(cv.layout, tMODIFIER) {
+		return nil, fmt.Errorf("no 'MODIFIER' in the layout '%s'", cv.Layout())
+	}
+	ncv := cv.clone()
+	ncv.modifier = m
+	return ncv, nil
+}
+
+// Metadata returns *Calver with build metadata.
+// Build metadata is ignored when comparing versions.
+func (cv *Calver) Metadata(m string) (*Calver, error) {
+	if !contains(cv.layout, tMETADATA) {
+		return nil, fmt.Errorf("no 'METADATA' in the layout '%s'", cv.Layout())
+	}
+	ncv := cv.clone()
+	ncv.metadata = m
+	return ncv, nil
+}
+
+// TrimSuffix returns *Calver enabled/diabled to trim the trailing version of a zero value or an empty string.
+func (cv *Calver) TrimSuffix(enable bool) *Calver {
+	ncv := cv.clone()
+	ncv.trimSuffix = enable
+	return ncv
+}
+
+// Strict returns *Calver enabled/disabled to validate calendar values strictly when parsing (enabled by default).
+// When enabled, impossible months, days, ISO weeks and so on are rejected with *RangeError,
+// as are calendar values that disagree with EPOCH in the same layout.
+// When disabled, they are normalized (e.g. month 13 of 2024 is January 2025) and EPOCH takes precedence.
+func (cv *Calver) Strict(enable bool) *Calver {
+	ncv := cv.clone()
+	ncv.lenient = !enable
+	return ncv
+}
+
+// TwoDigitYearStart returns *Calver that maps two-digit years (YY, 0Y, GG, 0G) into the 100-year window starting at the given year.
+// The default window is 2000-2099. For example, with 1950, '99' is parsed as 1999 and '49' is parsed as 2049.
+func (cv *Calver) TwoDigitYearStart(year int) *Calver {
+	ncv := cv.clone()
+	ncv.twoDigitYearStart = year
+	return ncv
+}
+
+// Compare returns an integer comparing two versions.
+// The result will be 0 if a == b, -1 if a is older than b, and +1 if a is newer than b.
+// Versions are compared by timestamp, then MAJOR, MINOR and MICRO, then ordered custom tokens,
+// then MODIFIER (a version without modifier is newer).
+// Modifiers are compared lexically unless the ordering is set by PrereleaseOrder (or PEP440Order).
+// If the ordering is set on only one of the versions, it is used. If different orderings are set, modifiers are compared lexically.
+func Compare(a, b *Calver) int {
+	switch {
+	case !a.ts.Equal(b.ts):
+		return a.ts.Compare(b.ts)
+	case a.major != b.major:
+		return cmp.Compare(a.major, b.major)
+	case a.minor != b.minor:
+		return cmp.Compare(a.minor, b.minor)
+	case a.micro != b.micro:
+		return cmp.Compare(a.micro, b.micro)
+	case compareCustom(a, b) != 0:
+		return compareCustom(a, b)
+	case modifierComparator(a, b) != nil:
+		return modifierComparator(a, b)(a.modifier, b.modifier)
+	case a.modifier == b.modifier:
+		return 0
+	case a.modifier == "":
+		return 1
+	case b.modifier == "":
+		return -1
+	default:
+		return strings.Compare(a.modifier, b.modifier)
+	}
+}
+
+// Equal reports whether cv and v are the same version.
+func (cv *Calver) Equal(v *Calver) bool {
+	return Compare(cv, v) == 0
+}
+
+// Before reports whether cv is older than v.
+func (cv *Calver) Before(v *Calver) bool {
+	return Compare(cv, v) < 0
+}
+
+// After reports whether cv is newer than v.
+func (cv *Calver) After(v *Calver) bool {
+	return Compare(cv, v) > 0
+}
+
+// parse parses version string using layout without optional segments.
+func (cv *Calver) parse(value string) (ncv *Calver, err error) {
 	org := value
+	// position of the token being parsed for ParseError
+	var (
+		offset   int
+		expected string
+		offsets  = map[string]int{}
+	)
 	defer func() {
 		if err != nil {
-			err = fmt.Errorf("failed to parse '%s' using layout '%s': %w", org, cv.Layout(), err)
+			var rerr *RangeError
+			if errors.As(err, &rerr) {
+				offset, expected = offsets[rerr.Token], rerr.Token
+			}
+			err = &ParseError{
+				Input:     org,
+				Layout:    cv.Layout(),
+				Offset:    offset,
+				Token:     expected,
+				Remaining: org[offset:],
+				Err:       err,
+			}
 		}
 	}()
 	ncv = cv.clone()
@@ -84,7 +413,7 @@ func (cv *Calver) Parse(value string) (ncv *Calver, err error) {
 					base = append([]token{t}, base...)
 				}
 			default:
-				if contain && tt.token() == tMODIFIER.token() {
+				if contain && isTextToken(tt) {
 					mods = append([]token{t}, mods...)
 				} else {
 					base = append([]token{t}, base...)
@@ -97,10 +426,20 @@ func (cv *Calver) Parse(value string) (ncv *Calver, err error) {
 	}
 
 	var (
-		p    string
-		week int
+		p        string
+		week     int
+		weekYear int
+		yday     int
+		hour     int
+		minute   int
+		epoch    *int64
+		qtr      int
+		// tokens of the parsed values for validation
+		qtrT, monthT, weekT, dayT, ydayT, hourT, minuteT token
 	)
 	for i, t := range base {
+		offset, expected = len(org)-len(value), t.token()
+		offsets[t.token()] = offset
 		// Calculate max length for current token based on subsequent tokens' requirements
 		minLenAfter := minLenUntilNextSep(base, i)
 		maxLen := 0
@@ -124,9 +463,35 @@ func (cv *Calver) Parse(value string) (ncv *Calver, err error) {
 			if err != nil {
 				return nil, err
 			}
-			if year < 2000 {
-				year += 2000
+			year, err = cv.fullYear(t, year)
+			if err != nil {
+				return nil, err
+			}
+		case contains([]token{tGGGG, tGG, t0G}, t):
+			p, value, err = t.trimPrefixWithMaxLen(value, maxLen)
+			if err != nil {
+				return nil, err
+			}
+			weekYear, err = strconv.Atoi(p)
+			if err != nil {
+				return nil, err
+			}
+			weekYear, err = cv.fullYear(t, weekYear)
+			if err != nil {
+				return nil, err
 			}
+		case contains([]token{tQ, t0Q}, t):
+			p, value, err = t.trimPrefixWithMaxLen(value, maxLen)
+			if err != nil {
+				return nil, err
+			}
+			qtr, err = strconv.Atoi(p)
+			if err != nil {
+				return nil, err
+			}
+			qtrT = t
+			// The first month of the quarter
+			month = time.Month((qtr-1)*3 + 1)
 		case contains([]token{tMM, t0M}, t):
 			p, value, err = t.trimPrefixWithMaxLen(value, maxLen)
 			if err != nil {
@@ -137,6 +502,7 @@ func (cv *Calver) Parse(value string) (ncv *Calver, err error) {
 				return nil, err
 			}
 			month = time.Month(m)
+			monthT = t
 		case contains([]token{tWW, t0W}, t):
 			p, value, err = t.trimPrefixWithMaxLen(value, maxLen)
 			if err != nil {
@@ -146,6 +512,7 @@ func (cv *Calver) Parse(value string) (ncv *Calver, err error) {
 			if err != nil {
 				return nil, err
 			}
+			weekT = t
 		case contains([]token{tDD, t0D}, t):
 			p, value, err = t.trimPrefixWithMaxLen(value, maxLen)
 			if err != nil {
@@ -155,6 +522,47 @@ func (cv *Calver) Parse(value string) (ncv *Calver, err error) {
 			if err != nil {
 				return nil, err
 			}
+			dayT = t
+		case contains([]token{tDDD, t0DDD}, t):
+			p, value, err = t.trimPrefixWithMaxLen(value, maxLen)
+			if err != nil {
+				return nil, err
+			}
+			yday, err = strconv.Atoi(p)
+			if err != nil {
+				return nil, err
+			}
+			ydayT = t
+		case contains([]token{tHH, t0H}, t):
+			p, value, err = t.trimPrefixWithMaxLen(value, maxLen)
+			if err != nil {
+				return nil, err
+			}
+			hour, err = strconv.Atoi(p)
+			if err != nil {
+				return nil, err
+			}
+			hourT = t
+		case contains([]token{tMI, t0MI}, t):
+			p, value, err = t.trimPrefixWithMaxLen(value, maxLen)
+			if err != nil {
+				return nil, err
+			}
+			minute, err = strconv.Atoi(p)
+			if err != nil {
+				return nil, err
+			}
+			minuteT = t
+		case contains([]token{tEPOCH}, t):
+			p, value, err = t.trimPrefixWithMaxLen(value, maxLen)
+			if err != nil {
+				return nil, err
+			}
+			e, err := strconv.ParseInt(p, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			epoch = &e
 		case contains([]token{tMAJOR}, t):
 			if value == "" && cv.trimSuffix {
 				ncv.major = 0
@@ -212,16 +620,36 @@ func (cv *Calver) Parse(value string) (ncv *Calver, err error) {
 				return nil, err
 			}
 			ncv.micro = m
-		case contains([]token{tMODIFIER}, t):
+		case isCustomToken(t):
 			if value == "" && cv.trimSuffix {
-				ncv.modifier = ""
 				continue
 			}
-			p, value, err = t.trimPrefix(value)
+			var trimed string
+			p, trimed, err = t.trimPrefixWithMaxLen(value, maxLen)
+			if err != nil {
+				if cv.trimSuffix && len(mods) > 0 {
+					break
+				}
+				return nil, err
+			}
+			value = trimed
+			v, err := t.(tokenCustom).parseValue(p)
 			if err != nil {
 				return nil, err
 			}
-			ncv.modifier = p
+			ncv.setValue(t.token(), v)
+		case contains([]token{tMODIFIER}, t):
+			if value == "" && cv.trimSuffix {
+				ncv.modifier = ""
+				continue
+			}
+			ncv.modifier, value = trimText(base, i, value)
+		case contains([]token{tMETADATA}, t):
+			if value == "" && cv.trimSuffix {
+				ncv.metadata = ""
+				continue
+			}
+			ncv.metadata, value = trimText(base, i, value)
 		default:
 			if value == "" && cv.trimSuffix {
 				continue
@@ -236,54 +664,94 @@ func (cv *Calver) Parse(value string) (ncv *Calver, err error) {
 			value = trimed
 		}
 	}
+	if !cv.lenient {
+		if err := validateCalendar(year, weekYear, []calendarValue{
+			{qtrT, qtr},
+			{monthT, int(month)},
+			{weekT, week},
+			{dayT, day},
+			{ydayT, yday},
+			{hourT, hour},
+			{minuteT, minute},
+		}, month); err != nil {
+			return nil, err
+		}
+	}
+	if weekYear > 0 {
+		if week == 0 {
+			week = 1
+		}
+		year = weekYear
+	}
 	if week > 0 {
 		year, month, day = isoweek.StartDate(year, week)
 	}
-	// Initialize (zeronize) hour and below when parsing
-	ncv.ts = time.Date(year, month, day, 0, 0, 0, 0, cv.loc)
+	if yday > 0 {
+		// time.Date normalizes the day of January into the day of the year
+		month = time.January
+		day = yday
+	}
+	// Initialize (zeronize) below the parsed time version when parsing
+	ncv.ts = time.Date(year, month, day, hour, minute, 0, 0, cv.loc)
+	if epoch != nil {
+		et := time.Unix(*epoch, 0).In(cv.loc)
+		if !cv.lenient {
+			if err := validateEpoch(base, et, ncv.ts); err != nil {
+				return nil, err
+			}
+		}
+		ncv.ts = et
+	}
 
 	if value != "" && cv.trimSuffix {
-		for _, t := range mods {
+		skip := -1
+		for i, t := range mods {
+			if i == skip {
+				continue
+			}
+			offset, expected = len(org)-len(value), t.token()
 			switch {
 			case contains([]token{tMODIFIER}, t):
 				if value == "" && cv.trimSuffix {
 					ncv.modifier = ""
 					continue
 				}
-				p, value, err = t.trimPrefix(value)
-				if err != nil {
-					return nil, err
+				ncv.modifier, value = trimText(mods, i, value)
+			case contains([]token{tMETADATA}, t):
+				if value == "" && cv.trimSuffix {
+					ncv.metadata = ""
+					continue
 				}
-				ncv.modifier = p
+				ncv.metadata, value = trimText(mods, i, value)
 			default:
 				if value == "" && cv.trimSuffix {
 					continue
 				}
-				_, value, err = t.trimPrefix(value)
+				var trimed string
+				_, trimed, err = t.trimPrefix(value)
 				if err != nil {
+					if i+1 < len(mods) && isTextToken(mods[i+1]) {
+						// The free text token following the separator is omitted
+						skip = i + 1
+						err = nil
+						continue
+					}
 					return nil, err
 				}
+				value = trimed
 			}
 		}
 	}
 
 	if value != "" {
+		offset, expected = len(org)-len(value), ""
 		return nil, errors.New("there are strings that could not be parsed")
 	}
 	return ncv, nil
 }
 
-// Parse version string using layout at the current time.
-func Parse(layout, value string) (*Calver, error) {
-	cv, err := New(layout)
-	if err != nil {
-		return nil, err
-	}
-	return cv.Parse(value)
-}
-
-// String returns version string.
-func (cv *Calver) String() string {
+// format returns version string using layout without optional segments.
+func (cv *Calver) format() string {
 	var s string
 	reversed := reverse(cv.layout)
 	rbase := []token{}
@@ -299,7 +767,7 @@ func (cv *Calver) String() string {
 				rbase = append(rbase, t)
 			}
 		default:
-			if contain && tt.token() == tMODIFIER.token() {
+			if contain && isTextToken(tt) {
 				rmods = append(rmods, t)
 			} else {
 				rbase = append(rbase, t)
@@ -309,18 +777,15 @@ func (cv *Calver) String() string {
 	}
 
 	// modifier suffix
+	// Each empty free text token is trimmed with the preceding separator.
 	trimable := cv.trimSuffix
 	for _, t := range rmods {
 		switch tt := t.(type) {
 		case tokenCal:
 			panic("invalid logic")
 		case tokenVer:
-			v := tt.verToString(cv.major, cv.minor, cv.micro, cv.modifier)
-			if trimable && (v == "0" || v == "") {
-				v = ""
-			} else {
-				trimable = false
-			}
+			v := tt.verToString(cv)
+			trimable = cv.trimSuffix && v == ""
 			s = v + s
 		case tokenSep:
 			if !trimable {
@@ -336,7 +801,15 @@ func (cv *Calver) String() string {
 			trimable = false
 			s = tt.timeToString(cv.ts.In(cv.loc)) + s
 		case tokenVer:
-			v := tt.verToString(cv.major, cv.minor, cv.micro, cv.modifier)
+			v := tt.verToString(cv)
+			if trimable && (v == "0" || v == "") {
+				v = ""
+			} else {
+				trimable = false
+			}
+			s = v + s
+		case tokenCustom:
+			v := tt.render(cv)
 			if trimable && (v == "0" || v == "") {
 				v = ""
 			} else {
@@ -352,139 +825,66 @@ func (cv *Calver) String() string {
 	return s
 }
 
-// Layout returns version layout.
-func (cv *Calver) Layout() string {
-	var s string
-	for _, t := range cv.layout {
-		s += t.token()
-	}
-	return s
-}
-
-// Next returns next version *Calver at the current time.
-func (cv *Calver) Next() (*Calver, error) {
-	now := time.Now()
-	return cv.NextWithTime(now)
-}
-
-// Next returns next version *Calver at the given time.
-func (cv *Calver) NextWithTime(now time.Time) (ncv *Calver, err error) {
-	defer func() {
-		if ncv != nil {
-			ncv.modifier = "" // clear modifier
+// fullYear returns the year of the parsed value of the year token.
+func (cv *Calver) fullYear(t token, y int) (int, error) {
+	if contains([]token{tYY, t0Y, tGG, t0G}, t) {
+		start := cv.twoDigitYearStart
+		if start == 0 {
+			start = defaultTwoDigitYearStart
 		}
-	}()
-	if cv.ts.UnixNano() > now.UnixNano() {
-		return nil, fmt.Errorf("[%v] is older than the current setting (%v)", now.Truncate(0), cv.ts)
-	}
-	ncv = cv.clone()
-	ncv.ts = now
-	if cv.String() != ncv.String() {
-		// if the time version is different and time version is first in the layout, reset major/minor/micro version.
-		if IsTimeVersionFirst(ncv.layout) {
-			ncv.major = 0
-			ncv.minor = 0
-			ncv.micro = 0
+		y += start - start%100
+		if y < start {
+			y += 100
 		}
-		return ncv, nil
 	}
-	if ncv.modifier != "" {
-		// if the modifier is set, no need to bump up major/minor/micro version.
-		return ncv, nil
+	if y < minYear || y > maxYear {
+		return 0, &RangeError{Token: t.token(), Value: y, Min: minYear, Max: maxYear}
 	}
-	if contains(ncv.layout, tMICRO) {
-		return ncv.Micro()
-	}
-	if contains(ncv.layout, tMINOR) {
-		return ncv.Minor()
-	}
-	if contains(ncv.layout, tMAJOR) {
-		return ncv.Major()
-	}
-	return nil, errors.New("failed to bump up version")
+	return y, nil
 }
 
-// Major returns next major version *Calver.
-func (cv *Calver) Major() (*Calver, error) {
-	if !contains(cv.layout, tMAJOR) {
-		return nil, fmt.Errorf("no 'MAJOR' in the layout '%s'", cv.Layout())
-	}
-	ncv := cv.clone()
-	ncv.major++
-	return ncv, nil
-}
-
-// Minor returns next minor version *Calver.
-func (cv *Calver) Minor() (*Calver, error) {
-	if !contains(cv.layout, tMINOR) {
-		return nil, fmt.Errorf("no 'MINOR' in the layout '%s'", cv.Layout())
-	}
-	ncv := cv.clone()
-	ncv.minor++
-	return ncv, nil
-}
-
-// Micro returns next micro version *Calver.
-func (cv *Calver) Micro() (*Calver, error) {
-	if !contains(cv.layout, tMICRO) {
-		return nil, fmt.Errorf("no 'MICRO' in the layout '%s'", cv.Layout())
-	}
-	ncv := cv.clone()
-	ncv.micro++
-	return ncv, nil
-}
-
-// Modifier returns *Calver with modifier.
-func (cv *Calver) Modifier(m string) (*Calver, error) {
-	if !contains(cv.layout, tMODIFIER) {
-		return nil, fmt.Errorf("no 'MODIFIER' in the layout '%s'", cv.Layout())
+func (cv *Calver) clone() *Calver {
+	return &Calver{
+		major:             cv.major,
+		minor:             cv.minor,
+		micro:             cv.micro,
+		modifier:          cv.modifier,
+		metadata:          cv.metadata,
+		values:            maps.Clone(cv.values),
+		ts:                cv.ts,
+		loc:               cv.loc,
+		layout:            cv.layout,
+		trimSuffix:        cv.trimSuffix,
+		lenient:           cv.lenient,
+		twoDigitYearStart: cv.twoDigitYearStart,
+		compareModifier:   cv.compareModifier,
+		modifierKey:       cv.modifierKey,
+		modifierOrder:     cv.modifierOrder,
+		absentCal:         cv.absentCal,
+		clock:             cv.clock,
 	}
-	ncv := cv.clone()
-	ncv.modifier = m
-	return ncv, nil
-}
-
-// TrimSuffix returns *Calver enabled/diabled to trim the trailing version of a zero value or an empty string.
-func (cv *Calver) TrimSuffix(enable bool) *Calver {
-	ncv := cv.clone()
-	ncv.trimSuffix = enable
-	return ncv
 }
 
-func (cv *Calver) clone() *Calver {
-	return &Calver{
-		major:      cv.major,
-		minor:      cv.minor,
-		micro:      cv.micro,
-		modifier:   cv.modifier,
-		ts:         cv.ts,
-		loc:        cv.loc,
-		layout:     cv.layout,
-		trimSuffix: cv.trimSuffix,
+// modifierComparator returns the comparator of modifiers used for both a and b. If nil, modifiers are compared lexically.
+// The result does not depend on the order of the arguments.
+func modifierComparator(a, b *Calver) func(x, y string) int {
+	switch {
+	case a.modifierOrder == b.modifierOrder, b.compareModifier == nil:
+		return a.compareModifier
+	case a.compareModifier == nil:
+		return b.compareModifier
 	}
+	return nil
 }
 
+// Sort sorts versions in descending order (newest first).
 func (cvs Calvers) Sort() {
 	sort.SliceStable(cvs, func(i, j int) bool {
-		switch {
-		case cvs[i].ts.UnixNano() != cvs[j].ts.UnixNano():
-			return cvs[i].ts.UnixNano() > cvs[j].ts.UnixNano()
-		case cvs[i].major != cvs[j].major:
-			return cvs[i].major > cvs[j].major
-		case cvs[i].minor != cvs[j].minor:
-			return cvs[i].minor > cvs[j].minor
-		case cvs[i].micro != cvs[j].micro:
-			return cvs[i].micro > cvs[j].micro
-		case cvs[i].modifier == "":
-			return true
-		case cvs[j].modifier == "":
-			return false
-		default:
-			return cvs[i].modifier > cvs[j].modifier
-		}
+		return Compare(cvs[i], cvs[j]) > 0
 	})
 }
 
+// Latest returns the newest version.
 func (cvs Calvers) Latest() (*Calver, error) {
 	if len(cvs) == 0 {
 		return nil, ErrNoVersions
@@ -493,19 +893,31 @@ func (cvs Calvers) Latest() (*Calver, error) {
 	return cvs[0], nil
 }
 
+// CheckLayout reports problems of the layout that do not prevent it from being used but may produce unexpected versions.
+func CheckLayout(layout string) error {
+	tokens, err := tokenizeLayout(layout)
+	if err != nil {
+		return err
+	}
+	if containsAny(tokens, []token{tYYYY, tYY, t0Y}) && containsAny(tokens, []token{tWW, t0W}) && !containsAny(tokens, []token{tGGGG, tGG, t0G}) {
+		return ErrCalendarYearWithISOWeek
+	}
+	return nil
+}
+
 // IsTimeVersionFirst returns true if the time version is first in the layout.
 func IsTimeVersionFirst(layout []token) bool {
-	return contains([]token{
-		tYYYY,
-		tYY,
-		t0Y,
-		tMM,
-		t0M,
-		tWW,
-		t0W,
-		tDD,
-		t0D,
-	}, layout[0])
+	if len(layout) == 0 {
+		return false
+	}
+	for _, t := range layout {
+		if _, ok := t.(tokenOptional); ok {
+			continue
+		}
+		_, ok := t.(tokenCal)
+		return ok
+	}
+	return false
 }
 
 func contains(layout []token, t token) bool {
@@ -525,6 +937,80 @@ func reverse(layout []token) []token {
 	return reversed
 }
 
+type calendarValue struct {
+	t token
+	v int
+}
+
+// validateCalendar validates the parsed calendar values.
+func validateCalendar(year, weekYear int, values []calendarValue, month time.Month) error {
+	if weekYear == 0 {
+		weekYear = year
+	}
+	for _, c := range values {
+		if c.t == nil {
+			continue
+		}
+		var minV, maxV int
+		switch {
+		case contains([]token{tQ, t0Q}, c.t):
+			minV, maxV = 1, 4
+		case contains([]token{tMM, t0M}, c.t):
+			minV, maxV = 1, 12
+		case contains([]token{tWW, t0W}, c.t):
+			minV, maxV = 1, isoWeeksInYear(weekYear)
+		case contains([]token{tDD, t0D}, c.t):
+			minV, maxV = 1, 31
+			if 1 <= month && month <= 12 {
+				minV, maxV = 1, daysInMonth(year, month)
+			}
+		case contains([]token{tDDD, t0DDD}, c.t):
+			minV, maxV = 1, daysInYear(year)
+		case contains([]token{tHH, t0H}, c.t):
+			minV, maxV = 0, 23
+		case contains([]token{tMI, t0MI}, c.t):
+			minV, maxV = 0, 59
+		default:
+			continue
+		}
+		if c.v < minV || c.v > maxV {
+			return &RangeError{Token: c.t.token(), Value: c.v, Min: minV, Max: maxV}
+		}
+	}
+	return nil
+}
+
+// validateEpoch validates that the calendar values parsed as t agree with the time of the epoch.
+func validateEpoch(layout []token, epoch, t time.Time) error {
+	for _, tt := range layout {
+		tc, ok := tt.(tokenCal)
+		if !ok || tc.token() == tEPOCH.token() {
+			continue
+		}
+		want := tc.timeToString(epoch)
+		if got := tc.timeToString(t); got != want {
+			v, _ := strconv.Atoi(got)
+			w, _ := strconv.Atoi(want)
+			return &RangeError{Token: tc.token(), Value: v, Min: w, Max: w}
+		}
+	}
+	return nil
+}
+
+func isoWeeksInYear(year int) int {
+	// December 28th is always in the last ISO week of the year
+	_, w := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return w
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
 // minLenUntilNextSep calculates the minimum length required by tokens from index+1 until the next separator.
 func minLenUntilNextSep(tokens []token, index int) int {
 	minLen := 0
@@ -541,12 +1027,23 @@ func minLenUntilNextSep(tokens []token, index int) int {
 func nextSepToken(tokens []token, index int) string {
 	for i := index + 1; i < len(tokens); i++ {
 		if sep, ok := tokens[i].(tokenSep); ok {
-			return sep.token()
+			return sep.t // the text, not the quoted or escaped form
 		}
 	}
 	return ""
 }
 
+// trimText returns the value of the free text token at the given index and the rest of value.
+// The free text token takes value until the next separator, or the whole value if separator not found.
+func trimText(tokens []token, index int, value string) (string, string) {
+	sep := nextSepToken(tokens, index)
+	if sep == "" {
+		return value, ""
+	}
+	l := lengthUntilSep(value, sep)
+	return value[:l], value[l:]
+}
+
 // lengthUntilSep returns the length of value until the given separator, or the full length if separator not found.
 func lengthUntilSep(value, sep string) int {
 	if sep == "" {
@@ -559,3 +1056,62 @@ func lengthUntilSep(value, sep string) int {
 	}
 	return len(value)
 }
+
+func padZeros(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return strings.Repeat("0", width-len(s)) + s
+}
+
+func trimZeros(s string) string {
+	s = strings.TrimLeft(s, "0")
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+func isSepToken(t token) bool {
+	switch t.(type) {
+	case tokenSep, tokenOptional:
+		return true
+	}
+	return false
+}
+
+// parseValues parses the version string built from the values of the numeric tokens, the modifier and the metadata.
+// Optional segments are included.
+func (cv *Calver) parseValues(values map[string]string, modifier, metadata string) (*Calver, error) {
+	var b strings.Builder
+	for _, t := range cv.layout {
+		switch tt := t.(type) {
+		case tokenOptional:
+		case tokenSep:
+			b.WriteString(tt.String())
+		case tokenCal:
+			v := trimZeros(values[t.token()])
+			if tt.digits > 0 {
+				v = padZeros(v, tt.digits)
+			}
+			b.WriteString(v)
+		default:
+			switch t.token() {
+			case tMODIFIER.token():
+				if modifier != "" {
+					b.WriteString(cv.modifierPrefix())
+				}
+				b.WriteString(modifier)
+			case tMETADATA.token():
+				b.WriteString(metadata)
+			default:
+				v := trimZeros(values[t.token()])
+				if tc, ok := t.(tokenCustom); ok {
+					v = tc.renderValue(cv, v)
+				}
+				b.WriteString(v)
+			}
+		}
+	}
+	return cv.Parse(b.String())
+}
@@ -3,6 +3,7 @@ package calver
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -20,6 +21,8 @@ func TestString(t *testing.T) {
 		{"YYYY.0M.0D", "2002.02.04"},
 		{"0Y.0M.MICRO", "02.02.3"},
 		{"0Y.0W.MICRO-MODIFIER", "02.06.3-dev"},
+		{"YYYY.0Q.MICRO", "2002.01.3"},
+		{"YY.DDD.MICRO", "2.35.3"},
 		{"MAJOR.MINOR.MICRO", "1.2.3"},
 	}
 	for _, tt := range tests {
@@ -107,6 +110,13 @@ func TestNextWithTime(t *testing.T) {
 		{"0Y.0W.MICRO-MODIFIER", testtime, "dev", "02.06.3", false},
 		{"0Y.0M.MICRO", testtime.AddDate(0, 1, 0), "", "02.03", false},
 		{"MAJOR.0Y.0M", testtime.AddDate(0, 1, 0), "", "1.02.03", false},
+		{"YYYY.Q.MICRO", testtime.AddDate(0, 1, 0), "", "2002.1.4", false},
+		{"YYYY.Q.MICRO", testtime.AddDate(0, 2, 0), "", "2002.2", false},
+		{"YYYY.0DDD.MICRO", testtime, "", "2002.035.4", false},
+		{"YYYY.0DDD.MICRO", testtime.AddDate(0, 0, 1), "", "2002.036", false},
+		{"YYYY.0M.0D.0H0MI.MICRO", testtime.Add(59 * time.Second), "", "2002.02.04.0000.4", false},
+		{"YYYY.0M.0D.0H0MI.MICRO", testtime.Add(90 * time.Minute), "", "2002.02.04.0130", false},
+		{"YYYY.0M.EPOCH", testtime.Add(time.Second), "", "2002.02.1012780801", false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.layout, func(t *testing.T) {
@@ -186,6 +196,58 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"YYYY.Q.MICRO", "2024.3.1",
+			&Calver{
+				micro: 1,
+				ts:    time.Date(2024, time.Month(7), 1, 0, 0, 0, 0, time.UTC),
+			},
+			false,
+		},
+		{
+			"YYYY.0Q.MICRO", "2024.04.1",
+			&Calver{
+				micro: 1,
+				ts:    time.Date(2024, time.Month(10), 1, 0, 0, 0, 0, time.UTC),
+			},
+			false,
+		},
+		{
+			"YY.DDD.MICRO", "24.292.0",
+			&Calver{
+				ts: time.Date(2024, time.Month(10), 18, 0, 0, 0, 0, time.UTC),
+			},
+			false,
+		},
+		{
+			"YYYY0DDD.MICRO", "2023060.1",
+			&Calver{
+				micro: 1,
+				ts:    time.Date(2023, time.Month(3), 1, 0, 0, 0, 0, time.UTC),
+			},
+			false,
+		},
+		{
+			"YYYY.0M.0D.HH0MI", "2024.10.18.905",
+			&Calver{
+				ts: time.Date(2024, time.Month(10), 18, 9, 5, 0, 0, time.UTC),
+			},
+			false,
+		},
+		{
+			"YYYY.0M.0D.0H0MI", "2024.10.18.1305",
+			&Calver{
+				ts: time.Date(2024, time.Month(10), 18, 13, 5, 0, 0, time.UTC),
+			},
+			false,
+		},
+		{
+			"YYYY.0M.EPOCH", "2024.10.1729209600",
+			&Calver{
+				ts: time.Date(2024, time.Month(10), 18, 0, 0, 0, 0, time.UTC),
+			},
+			false,
+		},
 		{
 			"YYYY.MMDD.MICRO", "2026.123.0",
 			&Calver{
@@ -387,6 +449,99 @@ func TestMajor(t *testing.T) {
 	}
 }
 
+func TestWith(t *testing.T) {
+	tests := []struct {
+		layout  string
+		major   int
+		minor   int
+		micro   int
+		want    string
+		wantErr bool
+	}{
+		{"YY.MAJOR.MINOR.MICRO", 10, 7, 3, "2.10.7.3", false},
+		{"YY.MAJOR.MINOR.MICRO", -1, 7, 3, "", true},
+		{"YY.MINOR.MICRO", 10, 7, 3, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d.%d.%d", tt.layout, tt.major, tt.minor, tt.micro), func(t *testing.T) {
+			cv, err := NewWithTime(tt.layout, testtime)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.WithMajor(tt.major)
+			if err == nil {
+				got, err = got.WithMinor(tt.minor)
+			}
+			if err == nil {
+				got, err = got.WithMicro(tt.micro)
+			}
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want error")
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v\nwant %v", got.String(), tt.want)
+			}
+			if cv.major != 0 || cv.minor != 0 || cv.micro != 0 {
+				t.Error("original version should not be changed")
+			}
+		})
+	}
+}
+
+func TestWithTime(t *testing.T) {
+	tests := []struct {
+		layout  string
+		ts      time.Time
+		want    string
+		wantErr bool
+	}{
+		{"YY.0M.MICRO", time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC), "24.10.0", false},
+		{"YYYY.0M.0D", time.Date(2024, 10, 15, 23, 0, 0, 0, time.FixedZone("", -2*60*60)), "2024.10.16", false},
+		{"YYYY.0M.0D", time.Date(2024, 10, 31, 13, 0, 0, 0, time.UTC), "2024.10.31", false},
+		{"YY.0M.0D.MICRO", time.Date(2024, 10, 31, 13, 5, 7, 9, time.UTC), "24.10.31.0", false},
+		{"YYYY.0M.0D.0H", time.Date(2024, 10, 31, 13, 5, 7, 9, time.UTC), "2024.10.31.13", false},
+		{"YYYY.0W", time.Date(2024, 10, 31, 13, 0, 0, 0, time.UTC), "2024.44", false},
+		{"GGGG.0W", time.Date(2024, 12, 31, 13, 0, 0, 0, time.UTC), "2025.01", false},
+		{"YYYY.DDD", time.Date(2024, 10, 31, 13, 0, 0, 0, time.UTC), "2024.305", false},
+		{"EPOCH", time.Date(2024, 10, 31, 13, 0, 0, 9, time.UTC), "1730379600", false},
+		{"MAJOR.MINOR.MICRO", time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.layout, tt.ts), func(t *testing.T) {
+			cv, err := NewWithTime(tt.layout, testtime)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.WithTime(tt.ts)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want error")
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v\nwant %v", got.String(), tt.want)
+			}
+			p, err := cv.Parse(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(p) {
+				t.Errorf("%v should be equal to %v", got, p)
+			}
+		})
+	}
+}
+
 func TestModifier(t *testing.T) {
 	tests := []struct {
 		layout   string
@@ -486,6 +641,13 @@ func TestNextWithTimeOlderError(t *testing.T) {
 	if err == nil {
 		t.Error("want error")
 	}
+	cv, err = NewWithTime("YYYY.0M.MICRO", time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Error(err)
+	}
+	if _, err := cv.NextWithTime(time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("want error")
+	}
 }
 
 func TestMinorError(t *testing.T) {
@@ -509,3 +671,327 @@ func TestMicroError(t *testing.T) {
 		t.Error("want error")
 	}
 }
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		layout string
+		a      string
+		b      string
+		want   int
+	}{
+		{"YY.0M.MICRO", "24.10.3", "24.10.1", 1},
+		{"YY.0M.MICRO", "24.10.1", "24.10.3", -1},
+		{"YY.0M.MICRO", "24.10.1", "24.10.1", 0},
+		{"YY.0M.MICRO", "24.09.9", "24.10.0", -1},
+		{"YY.0M.MICRO-MODIFIER", "24.10.3-", "24.10.1-rc", 1},
+		{"YY.0M.MICRO-MODIFIER", "24.10.1-", "24.10.1-rc", 1},
+		{"YY.0M.MICRO-MODIFIER", "24.10.1-beta", "24.10.1-alpha", 1},
+		{"YY.0M.MICRO-MODIFIER", "24.10.1-rc", "24.10.1-rc", 0},
+		{"MAJOR.MINOR.MICRO", "1.2.0", "1.10.0", -1},
+		{"YYYY.0M", "2300.01", "2200.01", 1},
+		{"YYYY.0M", "9999.12", "0001.01", 1},
+		{"YYYY.0M", "1600.01", "1700.01", -1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s/%s", tt.layout, tt.a, tt.b), func(t *testing.T) {
+			a, err := Parse(tt.layout, tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := Parse(tt.layout, tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Compare(a, b); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+			if got := a.Equal(b); got != (tt.want == 0) {
+				t.Errorf("Equal: got %v", got)
+			}
+			if got := a.Before(b); got != (tt.want < 0) {
+				t.Errorf("Before: got %v", got)
+			}
+			if got := a.After(b); got != (tt.want > 0) {
+				t.Errorf("After: got %v", got)
+			}
+		})
+	}
+}
+
+func TestCompareAgreesWithSort(t *testing.T) {
+	layout := "YYYY.0M.MICROMODIFIER"
+	versions := []string{"2012.12.0-dev", "2012.12.0", "2012.11.3", "2012.12.1-rc", "2012.12.1", "2013.01.0-alpha", "2012.12.1-beta"}
+	cvs := Calvers{}
+	for _, v := range versions {
+		cv, err := Parse(layout, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cvs = append(cvs, cv)
+	}
+	sorted := slices.Clone(cvs)
+	slices.SortFunc(sorted, func(a, b *Calver) int {
+		return Compare(b, a)
+	})
+	cvs.Sort()
+	for i := range cvs {
+		if cvs[i].String() != sorted[i].String() {
+			t.Errorf("got %v\nwant %v", sorted[i].String(), cvs[i].String())
+		}
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	tests := []struct {
+		layout       string
+		trimSuffix   bool
+		version      string
+		wantModifier string
+		wantMetadata string
+		want         string
+	}{
+		{"YY.0M.MICRO+METADATA", false, "24.10.2+build.5812.gabc123", "", "build.5812.gabc123", "24.10.2+build.5812.gabc123"},
+		{"YY.0M.MICRO-MODIFIER+METADATA", false, "24.10.2-rc.1+build.5812", "rc.1", "build.5812", "24.10.2-rc.1+build.5812"},
+		{"YY.0M.MICRO-MODIFIER+METADATA", true, "24.10.2-rc.1+build.5812", "rc.1", "build.5812", "24.10.2-rc.1+build.5812"},
+		{"YY.0M.MICRO-MODIFIER+METADATA", true, "24.10.2+build.5812", "", "build.5812", "24.10.2+build.5812"},
+		{"YY.0M.MICRO-MODIFIER+METADATA", true, "24.10.2-rc.1", "rc.1", "", "24.10.2-rc.1"},
+		{"YY.0M.MICRO-MODIFIER+METADATA", true, "24.10.2", "", "", "24.10.2"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v/%s", tt.layout, tt.trimSuffix, tt.version), func(t *testing.T) {
+			cv, err := New(tt.layout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.TrimSuffix(tt.trimSuffix).Parse(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.modifier != tt.wantModifier {
+				t.Errorf("got %v\nwant %v", got.modifier, tt.wantModifier)
+			}
+			if got.metadata != tt.wantMetadata {
+				t.Errorf("got %v\nwant %v", got.metadata, tt.wantMetadata)
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v\nwant %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataIgnoredByCompare(t *testing.T) {
+	a, err := Parse("YY.0M.MICRO+METADATA", "24.10.2+build.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := a.Metadata("build.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Equal(b) {
+		t.Errorf("%v and %v should be equal", a, b)
+	}
+	ncv, err := b.NextWithTime(time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "24.10.3+"; ncv.String() != want {
+		t.Errorf("got %v\nwant %v", ncv.String(), want)
+	}
+	cv, err := New("YY.0M.MICRO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cv.Metadata("build.1"); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestISOWeekYear(t *testing.T) {
+	tests := []struct {
+		layout string
+		ts     time.Time
+		want   string
+	}{
+		{"GGGG.0W", time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), "2025.01"},
+		{"GG.WW", time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), "25.1"},
+		{"0G.0W", time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC), "20.53"},
+		{"GGGG.0W", time.Date(2024, 12, 29, 0, 0, 0, 0, time.UTC), "2024.52"},
+		{"GGGG.0W.MICRO", time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC), "2024.42.0"},
+		{"GG.WW.HH0MI", time.Date(2024, 12, 31, 13, 5, 0, 0, time.UTC), "25.1.1305"},
+		{"0G.0W.MICRO", time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC), "20.53.0"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.layout, tt.ts), func(t *testing.T) {
+			cv, err := NewWithTime(tt.layout, tt.ts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := cv.String()
+			if got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+			parsed, err := cv.Parse(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.String() != tt.want {
+				t.Errorf("got %v\nwant %v", parsed.String(), tt.want)
+			}
+			if parsed.ts.After(tt.ts) {
+				t.Errorf("parsed %v is after %v", parsed.ts, tt.ts)
+			}
+		})
+	}
+}
+
+func TestCheckLayout(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   error
+	}{
+		{"YY.0M.MICRO", nil},
+		{"GGGG.0W.MICRO", nil},
+		{"YYYY.0W.MICRO", ErrCalendarYearWithISOWeek},
+		{"0Y.WW", ErrCalendarYearWithISOWeek},
+	}
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			got := CheckLayout(tt.layout)
+			if !errors.Is(got, tt.want) {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+	if err := CheckLayout("YY.0Y"); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestTwoDigitYearStart(t *testing.T) {
+	tests := []struct {
+		layout    string
+		start     int
+		value     string
+		wantYear  int
+		wantError bool
+	}{
+		{"YYYY.0M.MICRO", 0, "1999.12.1", 1999, false},
+		{"YYYY.0M.MICRO", 0, "2024.12.1", 2024, false},
+		{"YYYY.0M.MICRO", 0, "0000.12.1", 0, true},
+		{"YY.0M.MICRO", 0, "99.12.1", 2099, false},
+		{"YY.0M.MICRO", 0, "24.12.1", 2024, false},
+		{"YY.0M.MICRO", 1950, "99.12.1", 1999, false},
+		{"YY.0M.MICRO", 1950, "49.12.1", 2049, false},
+		{"YY.0M.MICRO", 1950, "50.12.1", 1950, false},
+		{"0Y.0M.MICRO", 2050, "05.12.1", 2105, false},
+		{"0G.0W.MICRO", 1950, "98.01.1", 1997, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d/%s", tt.layout, tt.start, tt.value), func(t *testing.T) {
+			cv, err := New(tt.layout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.start != 0 {
+				cv = cv.TwoDigitYearStart(tt.start)
+			}
+			got, err := cv.Parse(tt.value)
+			if err != nil {
+				if !tt.wantError {
+					t.Errorf("got error: %v", err)
+				}
+				var rerr *RangeError
+				if !errors.As(err, &rerr) {
+					t.Errorf("got %T\nwant %T", err, rerr)
+				}
+				return
+			}
+			if tt.wantError {
+				t.Error("want error")
+			}
+			if got.ts.Year() != tt.wantYear {
+				t.Errorf("got %v\nwant %v", got.ts.Year(), tt.wantYear)
+			}
+			if got.String() != tt.value {
+				t.Errorf("got %v\nwant %v", got.String(), tt.value)
+			}
+		})
+	}
+}
+
+func TestStrict(t *testing.T) {
+	tests := []struct {
+		layout    string
+		value     string
+		wantToken string
+		lenient   string
+	}{
+		{"YY.MM.MICRO", "24.13.1", "MM", "25.1.1"},
+		{"YY.0M.MICRO", "24.00.1", "0M", "23.12.1"},
+		{"YYYY.0M.0D", "2024.02.30", "0D", "2024.03.01"},
+		{"YYYY.0M.0D", "2023.02.29", "0D", "2023.03.01"},
+		{"YYYY.0M.0D", "2024.04.31", "0D", "2024.05.01"},
+		{"YYYY.0M.0D", "2024.01.00", "0D", "2023.12.31"},
+		{"YYYY.0W", "2024.60", "0W", ""},
+		{"GGGG.0W", "2024.53", "0W", ""},
+		{"YYYY.0Q", "2024.05", "0Q", ""},
+		{"YYYY.0DDD", "2023.366", "0DDD", "2024.001"},
+		{"YYYY.0M.0D.0H0MI", "2024.10.18.2460", "0H", ""},
+		{"YYYY.0M.0D.0H0MI", "2024.10.18.2360", "0MI", ""},
+		{"YYYY.0M.EPOCH", "2024.11.1729209600", "0M", "2024.10.1729209600"},
+		{"YY.0M.EPOCH", "23.10.1729209600", "YY", "24.10.1729209600"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.value), func(t *testing.T) {
+			cv, err := New(tt.layout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = cv.Parse(tt.value)
+			if err == nil {
+				t.Fatal("want error")
+			}
+			var rerr *RangeError
+			if !errors.As(err, &rerr) {
+				t.Fatalf("got %T\nwant %T", err, rerr)
+			}
+			if rerr.Token != tt.wantToken {
+				t.Errorf("got %v\nwant %v", rerr.Token, tt.wantToken)
+			}
+			if tt.lenient == "" {
+				return
+			}
+			got, err := cv.Strict(false).Parse(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.lenient {
+				t.Errorf("got %v\nwant %v", got.String(), tt.lenient)
+			}
+		})
+	}
+}
+
+func TestStrictValid(t *testing.T) {
+	tests := []struct {
+		layout string
+		value  string
+	}{
+		{"YYYY.0M.0D", "2024.02.29"},
+		{"YYYY.0M.0D", "2024.12.31"},
+		{"YYYY.0W", "2020.53"},
+		{"YYYY.0DDD", "2024.366"},
+		{"YYYY.0M.0D.0H0MI", "2024.10.18.2359"},
+		{"YYYY.0M.0D.EPOCH", "2024.10.18.1729209600"},
+		{"YYYY.0M.EPOCH", "2024.10.1729295999"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.value), func(t *testing.T) {
+			if _, err := Parse(tt.layout, tt.value); err != nil {
+				t.Errorf("got error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package calver
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time for New, Next and NextPrerelease.
+type Clock interface {
+	Now() time.Time
+}
+
+// Option configures *Calver created by New and NewWithTime.
+type Option func(cv *Calver)
+
+// WithClock sets the clock used to get the current time. If c is nil, the system clock is used.
+func WithClock(c Clock) Option {
+	return func(cv *Calver) {
+		cv.clock = c
+	}
+}
+
+// WithLocation sets the location of the version. If loc is nil, UTC is used.
+func WithLocation(loc *time.Location) Option {
+	return func(cv *Calver) {
+		if loc == nil {
+			loc = time.UTC
+		}
+		cv.loc = loc
+	}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+type fixedClock struct {
+	t time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
+
+// FixedClock returns Clock that always returns t.
+func FixedClock(t time.Time) Clock {
+	return fixedClock{t: t}
+}
+
+// FakeClock is Clock whose time is set and advanced manually. It is safe for concurrent use.
+type FakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+// NewFakeClock returns *FakeClock at the given time.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{t: t}
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+// Set sets the current time of the clock.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+// Advance advances the current time of the clock by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+// now returns the current time of the clock of cv in UTC.
+func (cv *Calver) now() time.Time {
+	c := cv.clock
+	if c == nil {
+		c = systemClock{}
+	}
+	return c.Now().UTC()
+}
+
+// newCalver returns *Calver at the current time of the clock with the options applied.
+// The location is UTC unless WithLocation is given.
+func newCalver(tokens []token, opts []Option) *Calver {
+	cv := &Calver{
+		loc:    time.UTC,
+		layout: tokens,
+	}
+	for _, opt := range opts {
+		opt(cv)
+	}
+	cv.ts = cv.now()
+	return cv
+}
@@ -0,0 +1,118 @@
+package calver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithClock(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	// 2024-10-31 23:30 UTC is 2024-11-01 08:30 JST
+	now := time.Date(2024, 11, 1, 8, 30, 0, 0, jst)
+	tests := []struct {
+		opts     []Option
+		want     string
+		wantNext string
+	}{
+		{[]Option{WithClock(FixedClock(now))}, "2024.10.31.0", "2024.10.31.1"},
+		{[]Option{WithClock(FixedClock(now)), WithLocation(jst)}, "2024.11.01.0", "2024.11.01.1"},
+		{[]Option{WithClock(FixedClock(now)), WithLocation(nil)}, "2024.10.31.0", "2024.10.31.1"},
+		{[]Option{WithLocation(jst), WithClock(FixedClock(now))}, "2024.11.01.0", "2024.11.01.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			cv, err := New("YYYY.0M.0D.MICRO", tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cv.String(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+			ncv, err := cv.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ncv.String(); got != tt.wantNext {
+				t.Errorf("got %v\nwant %v", got, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestWithClockNil(t *testing.T) {
+	cv, err := New("YYYY.0M.0D", WithClock(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cv.loc != time.UTC {
+		t.Errorf("got %v\nwant %v", cv.loc, time.UTC)
+	}
+	if d := time.Since(cv.ts); d < 0 || d > time.Minute {
+		t.Errorf("got %v\nwant the current time", cv.ts)
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 10, 31, 12, 0, 0, 0, time.UTC))
+	cv, err := New("YYYY.0M.0D.MICRO[-MODIFIER]", WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		advance time.Duration
+		next    func(cv *Calver) (*Calver, error)
+		want    string
+	}{
+		{0, (*Calver).Next, "2024.10.31.1"},
+		{time.Hour, (*Calver).Next, "2024.10.31.2"},
+		{12 * time.Hour, (*Calver).Next, "2024.11.01.0"},
+		{time.Hour, func(cv *Calver) (*Calver, error) { return cv.NextPrerelease("rc") }, "2024.11.01.1-rc.1"},
+		{24 * time.Hour, func(cv *Calver) (*Calver, error) { return cv.NextPrerelease("rc") }, "2024.11.01.1-rc.2"},
+	}
+	for _, tt := range tests {
+		clock.Advance(tt.advance)
+		ncv, err := tt.next(cv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ncv.String(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+		cv = ncv
+	}
+	clock.Set(time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC))
+	if _, err := cv.Next(); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestNewWithTimeLocation(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 10, 31, 23, 30, 0, 0, time.UTC)
+	tests := []struct {
+		opts []Option
+		want string
+	}{
+		{nil, "2024.10.31"},
+		{[]Option{WithLocation(jst)}, "2024.11.01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			cv, err := NewWithTime("YYYY.0M.0D", now, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cv.String(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+	r := NewRegistry()
+	cv, err := r.NewWithTime("YYYY.0M.0D", now.In(jst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cv.String(); got != "2024.11.01" {
+		t.Errorf("got %v\nwant %v", got, "2024.11.01")
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2023 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/k1LoW/calver"
+	"github.com/spf13/cobra"
+)
+
+var to string
+
+var convertCmd = &cobra.Command{
+	Use:   "convert [VERSION...]",
+	Short: "convert versions to another layout",
+	Long:  `convert versions to another layout.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if to == "" {
+			return errors.New("--to is required")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cv, err := calver.New(layout)
+		if err != nil {
+			return err
+		}
+		cv = orderBy(cv.TrimSuffix(trimSuffix))
+		warnLayout(layout, to)
+		versions, err := readVersions(args)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			return errors.New("no versions to convert")
+		}
+		var errs error
+		for _, v := range versions {
+			ccv, err := cv.Parse(v)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			ccv, err = ccv.ConvertTo(to)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			s, err := formatVersion(ccv)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			fmt.Println(s)
+		}
+		return errs
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+	convertCmd.Flags().StringVarP(&to, "to", "", "", "layout to convert to")
+}
@@ -26,7 +26,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/k1LoW/calver"
 	"github.com/k1LoW/calver/version"
@@ -41,7 +43,11 @@ var (
 	minor      bool
 	micro      bool
 	modifier   string
+	metadata   string
+	nextPre    string
+	release    bool
 	trimSuffix bool
+	format     string
 )
 
 var rootCmd = &cobra.Command{
@@ -52,13 +58,13 @@ var rootCmd = &cobra.Command{
 	Version:      version.Version,
 	Args: func(cmd *cobra.Command, args []string) error {
 		enabled := []bool{}
-		for _, f := range []bool{next, major, minor, micro} {
+		for _, f := range []bool{next, major, minor, micro, nextPre != "", release} {
 			if f {
 				enabled = append(enabled, f)
 			}
 		}
 		if len(enabled) > 1 {
-			return errors.New("only one of --next, --major, --minor, --micro can be enabled")
+			return errors.New("only one of --next, --major, --minor, --micro, --next-pre, --release can be enabled")
 		}
 		return nil
 	},
@@ -67,25 +73,11 @@ var rootCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		cv = cv.TrimSuffix(trimSuffix)
-		var versions []string
-		switch {
-		case len(args) > 0:
-			versions = args
-		case !isatty.IsTerminal(os.Stdin.Fd()):
-			stdin, err := io.ReadAll(os.Stdin)
-			if err != nil {
-				return err
-			}
-			lines := strings.Split(strings.Trim(string(stdin), " \n"), "\n")
-			for _, l := range lines {
-				splited := strings.Split(l, " ")
-				for _, ll := range splited {
-					if ll != "" {
-						versions = append(versions, ll)
-					}
-				}
-			}
+		warnLayout(layout)
+		cv = orderBy(cv.TrimSuffix(trimSuffix))
+		versions, err := readVersions(args)
+		if err != nil {
+			return err
 		}
 
 		var errs error
@@ -125,6 +117,16 @@ var rootCmd = &cobra.Command{
 				if err != nil {
 					return err
 				}
+			case nextPre != "":
+				cv, err = cv.NextPrerelease(nextPre)
+				if err != nil {
+					return err
+				}
+			case release:
+				cv, err = cv.Release()
+				if err != nil {
+					return err
+				}
 			}
 		}
 
@@ -135,7 +137,18 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
-		fmt.Println(cv.String())
+		if metadata != "" {
+			cv, err = cv.Metadata(metadata)
+			if err != nil {
+				return err
+			}
+		}
+
+		v, err := formatVersion(cv)
+		if err != nil {
+			return err
+		}
+		fmt.Println(v)
 		return nil
 	},
 }
@@ -143,16 +156,103 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
+		printParseErrors(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
+// printParseErrors prints a pointer to the position where parsing failed for each *calver.ParseError in err.
+func printParseErrors(w io.Writer, err error) {
+	var e interface{ Unwrap() []error }
+	if errors.As(err, &e) {
+		for _, err := range e.Unwrap() {
+			printParseErrors(w, err)
+		}
+		return
+	}
+	var perr *calver.ParseError
+	if !errors.As(err, &perr) {
+		return
+	}
+	expected := "end of the version"
+	if perr.Token != "" {
+		expected = fmt.Sprintf("'%s'", perr.Token)
+	}
+	// Pad by the number of characters, not bytes, before the position
+	pad := utf8.RuneCountInString(perr.Input[:min(perr.Offset, len(perr.Input))])
+	_, _ = fmt.Fprintf(w, "\n  %s\n  %s^ expected %s\n", perr.Input, strings.Repeat(" ", pad), expected)
+}
+
+// orderBy returns *calver.Calver that compares modifiers in the ordering of the output format.
+func orderBy(cv *calver.Calver) *calver.Calver {
+	switch format {
+	case "semver":
+		return cv.PrereleaseOrder()
+	case "pep440":
+		return cv.PEP440Order()
+	}
+	return cv
+}
+
+// formatVersion returns the version string in the output format.
+func formatVersion(cv *calver.Calver) (string, error) {
+	switch format {
+	case "calver":
+		return cv.String(), nil
+	case "semver":
+		return cv.Semver()
+	case "pep440":
+		return cv.PEP440()
+	}
+	return "", fmt.Errorf("invalid format '%s'", format)
+}
+
+// readVersions returns versions from args or stdin.
+func readVersions(args []string) ([]string, error) {
+	var versions []string
+	switch {
+	case len(args) > 0:
+		versions = args
+	case !isatty.IsTerminal(os.Stdin.Fd()):
+		stdin, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		lines := strings.Split(strings.Trim(string(stdin), " \n"), "\n")
+		for _, l := range lines {
+			splited := strings.Split(l, " ")
+			for _, ll := range splited {
+				if ll != "" {
+					versions = append(versions, ll)
+				}
+			}
+		}
+	}
+	return versions, nil
+}
+
 func init() {
-	rootCmd.Flags().StringVarP(&layout, "layout", "l", "YY.0M.MICRO", "version layout")
+	rootCmd.PersistentFlags().StringVarP(&layout, "layout", "l", "YY.0M.MICRO", "version layout")
 	rootCmd.Flags().BoolVarP(&next, "next", "n", false, "show next version of parsed version")
 	rootCmd.Flags().BoolVarP(&major, "major", "", false, "show next major version of parsed version")
 	rootCmd.Flags().BoolVarP(&minor, "minor", "", false, "show next minor version of parsed version")
 	rootCmd.Flags().BoolVarP(&micro, "micro", "", false, "show next micro version of parsed version")
 	rootCmd.Flags().StringVarP(&modifier, "modifier", "", "", "set modifier to parsed version")
-	rootCmd.Flags().BoolVarP(&trimSuffix, "trim-suffix", "", false, "trim the trailing version of a zero value or an empty string")
+	rootCmd.Flags().StringVarP(&metadata, "metadata", "", "", "set build metadata to parsed version")
+	rootCmd.Flags().StringVarP(&nextPre, "next-pre", "", "", "show next pre-release version of the channel of parsed version")
+	rootCmd.Flags().BoolVarP(&release, "release", "", false, "show release version (without modifier) of parsed version")
+	rootCmd.PersistentFlags().StringVarP(&format, "format", "", "calver", "output format (calver, semver, pep440)")
+	rootCmd.PersistentFlags().BoolVarP(&trimSuffix, "trim-suffix", "", false, "trim the trailing version of a zero value or an empty string")
+}
+
+// warnLayout prints the problems of the layouts reported by CheckLayout.
+func warnLayout(layouts ...string) {
+	for i, l := range layouts {
+		if slices.Contains(layouts[:i], l) {
+			continue
+		}
+		if err := calver.CheckLayout(l); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: %v in the layout '%s'\n", err, l)
+		}
+	}
 }
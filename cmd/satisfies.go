@@ -0,0 +1,79 @@
+/*
+Copyright © 2023 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/k1LoW/calver"
+	"github.com/spf13/cobra"
+)
+
+var satisfiesCmd = &cobra.Command{
+	Use:   "satisfies [CONSTRAINT] [VERSION...]",
+	Short: "show versions that satisfy the constraint",
+	Long:  `show versions that satisfy the constraint.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cv, err := calver.New(layout)
+		if err != nil {
+			return err
+		}
+		warnLayout(layout)
+		cv = orderBy(cv.TrimSuffix(trimSuffix))
+		c, err := cv.ParseConstraint(args[0])
+		if err != nil {
+			return err
+		}
+		versions, err := readVersions(args[1:])
+		if err != nil {
+			return err
+		}
+		var errs error
+		cvs := calver.Calvers{}
+		for _, v := range versions {
+			ccv, err := cv.Parse(v)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			cvs = append(cvs, ccv)
+		}
+		satisfied := cvs.Filter(c)
+		if len(satisfied) == 0 {
+			return errors.Join(fmt.Errorf("no versions satisfy the constraint '%s'", c), errs)
+		}
+		for _, ccv := range satisfied {
+			s, err := formatVersion(ccv)
+			if err != nil {
+				return err
+			}
+			fmt.Println(s)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(satisfiesCmd)
+}
@@ -0,0 +1,105 @@
+package calver
+
+import (
+	"time"
+)
+
+// Components is a snapshot of the components of a version.
+type Components struct {
+	Time     time.Time
+	Year     int
+	Month    time.Month
+	ISOYear  int
+	ISOWeek  int
+	Day      int
+	Major    int
+	Minor    int
+	Micro    int
+	Modifier string
+	Metadata string
+	// Values is the values of custom tokens.
+	Values   map[string]string
+	Location *time.Location
+}
+
+// Time returns the time of the version in the location of the version.
+func (cv *Calver) Time() time.Time {
+	return cv.ts.In(cv.loc)
+}
+
+// Year returns the year of the version.
+func (cv *Calver) Year() int {
+	return cv.Time().Year()
+}
+
+// Month returns the month of the version.
+func (cv *Calver) Month() time.Month {
+	return cv.Time().Month()
+}
+
+// ISOWeek returns the ISO 8601 year and week number of the version.
+func (cv *Calver) ISOWeek() (year, week int) {
+	return cv.Time().ISOWeek()
+}
+
+// Day returns the day of the month of the version.
+func (cv *Calver) Day() int {
+	return cv.Time().Day()
+}
+
+// Location returns the location of the version.
+func (cv *Calver) Location() *time.Location {
+	return cv.loc
+}
+
+// MajorValue returns the MAJOR value of the version.
+func (cv *Calver) MajorValue() int {
+	return cv.major
+}
+
+// MinorValue returns the MINOR value of the version.
+func (cv *Calver) MinorValue() int {
+	return cv.minor
+}
+
+// MicroValue returns the MICRO value of the version.
+func (cv *Calver) MicroValue() int {
+	return cv.micro
+}
+
+// ModifierValue returns the MODIFIER value of the version.
+func (cv *Calver) ModifierValue() string {
+	return cv.modifier
+}
+
+// MetadataValue returns the METADATA value of the version.
+func (cv *Calver) MetadataValue() string {
+	return cv.metadata
+}
+
+// Components returns a snapshot of the components of the version.
+func (cv *Calver) Components() Components {
+	t := cv.Time()
+	isoYear, isoWeek := t.ISOWeek()
+	values := map[string]string{}
+	for _, tt := range cv.layout {
+		if tc, ok := tt.(tokenCustom); ok {
+			values[tc.token()] = tc.render(cv)
+		}
+	}
+	return Components{
+		Time:     t,
+		Year:     t.Year(),
+		Month:    t.Month(),
+		ISOYear:  isoYear,
+		ISOWeek:  isoWeek,
+		Day:      t.Day(),
+		Major:    cv.major,
+		Minor:    cv.minor,
+		Micro:    cv.micro,
+		Modifier: cv.modifier,
+		Metadata: cv.metadata,
+		Values:   values,
+		Location: cv.loc,
+	}
+}
@@ -0,0 +1,95 @@
+package calver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestComponents(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		layout string
+		value  string
+		loc    *time.Location
+		want   Components
+	}{
+		{"YYYY.0M.0D.MICRO", "2024.10.15.3", time.UTC, Components{
+			Time:     time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC),
+			Year:     2024,
+			Month:    time.October,
+			ISOYear:  2024,
+			ISOWeek:  42,
+			Day:      15,
+			Micro:    3,
+			Values:   map[string]string{},
+			Location: time.UTC,
+		}},
+		{"YY.MAJOR.MINOR-MODIFIER+METADATA", "24.1.2-rc.1+abc", jst, Components{
+			Time:     time.Date(2024, 1, 1, 0, 0, 0, 0, jst),
+			Year:     2024,
+			Month:    time.January,
+			ISOYear:  2024,
+			ISOWeek:  1,
+			Day:      1,
+			Major:    1,
+			Minor:    2,
+			Modifier: "rc.1",
+			Metadata: "abc",
+			Values:   map[string]string{},
+			Location: jst,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			cv, err := NewWithTime(tt.layout, time.Now().In(tt.loc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			cv, err = cv.Parse(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := cv.Components()
+			opt := cmp.Comparer(func(a, b *time.Location) bool { return a.String() == b.String() })
+			if diff := cmp.Diff(got, tt.want, opt); diff != "" {
+				t.Error(diff)
+			}
+			if !cv.Time().Equal(tt.want.Time) {
+				t.Errorf("got %v\nwant %v", cv.Time(), tt.want.Time)
+			}
+			if cv.Year() != tt.want.Year || cv.Month() != tt.want.Month || cv.Day() != tt.want.Day {
+				t.Errorf("got %d-%d-%d\nwant %d-%d-%d", cv.Year(), cv.Month(), cv.Day(), tt.want.Year, tt.want.Month, tt.want.Day)
+			}
+			if y, w := cv.ISOWeek(); y != tt.want.ISOYear || w != tt.want.ISOWeek {
+				t.Errorf("got %d-W%d\nwant %d-W%d", y, w, tt.want.ISOYear, tt.want.ISOWeek)
+			}
+			if cv.MajorValue() != tt.want.Major || cv.MinorValue() != tt.want.Minor || cv.MicroValue() != tt.want.Micro {
+				t.Errorf("got %d.%d.%d\nwant %d.%d.%d", cv.MajorValue(), cv.MinorValue(), cv.MicroValue(), tt.want.Major, tt.want.Minor, tt.want.Micro)
+			}
+			if cv.ModifierValue() != tt.want.Modifier || cv.MetadataValue() != tt.want.Metadata {
+				t.Errorf("got %s+%s\nwant %s+%s", cv.ModifierValue(), cv.MetadataValue(), tt.want.Modifier, tt.want.Metadata)
+			}
+			if cv.Location().String() != tt.want.Location.String() {
+				t.Errorf("got %v\nwant %v", cv.Location(), tt.want.Location)
+			}
+		})
+	}
+}
+
+func TestComponentsCustom(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(TokenDef{Name: "BUILD", Counter: true}); err != nil {
+		t.Fatal(err)
+	}
+	cv, err := r.NewWithTime("YYYY.0M.BUILD", testtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := cv.Components().Values
+	want := map[string]string{"BUILD": "0"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,222 @@
+package calver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Constraint is a set of version constraints.
+//
+// The constraint expression consists of comparisons joined by ',' (AND) and '||' (OR).
+// Supported operators are '=', '!=', '>', '>=', '<', '<=' and '~'.
+// A version in the expression may specify only the leading part of the layout (e.g. '24.10' for 'YY.0M.MICRO')
+// or end with a wildcard ('*' or 'x') to point to a period:
+//
+//	>=24.01.0, <25.01.0
+//	~24.10
+//	24.10.* || 24.11.*
+//
+// '~V' matches any version in the same period as V and not older than V.
+// When V specifies the whole layout, its last segment is dropped to determine the period.
+type Constraint struct {
+	expr   string
+	groups [][]condition
+}
+
+type condition struct {
+	op      string
+	version *Calver
+	// period is the layout of the leading part specified by version.
+	period []token
+}
+
+var constraintOperators = []string{"!=", ">=", "<=", "==", "=", ">", "<", "~"}
+
+// ParseConstraint parses constraint expression using layout.
+func ParseConstraint(layout, expr string) (Constraint, error) {
+	cv, err := New(layout)
+	if err != nil {
+		return Constraint{}, err
+	}
+	return cv.ParseConstraint(expr)
+}
+
+// ParseConstraint parses constraint expression using layout.
+func (cv *Calver) ParseConstraint(expr string) (c Constraint, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("failed to parse constraint '%s' using layout '%s': %w", expr, cv.Layout(), err)
+		}
+	}()
+	c = Constraint{expr: expr}
+	for _, or := range strings.Split(expr, "||") {
+		group := []condition{}
+		for _, and := range strings.Split(or, ",") {
+			and = strings.TrimSpace(and)
+			if and == "" {
+				return Constraint{}, errors.New("empty constraint")
+			}
+			cond, err := cv.parseCondition(and)
+			if err != nil {
+				return Constraint{}, err
+			}
+			group = append(group, cond)
+		}
+		c.groups = append(c.groups, group)
+	}
+	return c, nil
+}
+
+// Check reports whether the version satisfies the constraint.
+func (c Constraint) Check(v *Calver) bool {
+	for _, group := range c.groups {
+		ok := true
+		for _, cond := range group {
+			if !cond.check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns constraint expression.
+func (c Constraint) String() string {
+	return c.expr
+}
+
+// Filter returns versions that satisfy the constraint.
+func (cvs Calvers) Filter(c Constraint) Calvers {
+	filtered := Calvers{}
+	for _, cv := range cvs {
+		if c.Check(cv) {
+			filtered = append(filtered, cv)
+		}
+	}
+	return filtered
+}
+
+func (cv *Calver) parseCondition(s string) (condition, error) {
+	op := "="
+	for _, o := range constraintOperators {
+		if strings.HasPrefix(s, o) {
+			op = o
+			s = strings.TrimSpace(strings.TrimPrefix(s, o))
+			break
+		}
+	}
+	if op == "==" {
+		op = "="
+	}
+	if v, err := cv.Parse(s); err == nil {
+		cond := condition{op: op, version: v}
+		if op == "~" {
+			cond.period = trimLastSegment(cv.layout)
+		}
+		return cond, nil
+	}
+	for _, w := range []string{"*", "x", "X"} {
+		if strings.HasSuffix(s, w) {
+			if op != "=" && op != "!=" {
+				return condition{}, fmt.Errorf("operator '%s' can not be used with wildcard", op)
+			}
+			s = strings.TrimSuffix(s, w)
+			break
+		}
+	}
+	// Parse as the leading part of the layout.
+	for i := len(cv.layout) - 1; i > 0; i-- {
+		pcv := cv.clone()
+		pcv.layout = cv.layout[:i]
+		v, err := pcv.Parse(s)
+		if err != nil {
+			continue
+		}
+		if op == "~" {
+			// '~' with the leading part is the same as the period.
+			op = "="
+		}
+		return condition{op: op, version: v, period: pcv.layout}, nil
+	}
+	return condition{}, fmt.Errorf("could not parse version '%s'", s)
+}
+
+func (c condition) check(v *Calver) bool {
+	if c.period == nil {
+		r := Compare(v, c.version)
+		switch c.op {
+		case "=":
+			return r == 0
+		case "!=":
+			return r != 0
+		case ">":
+			return r > 0
+		case ">=":
+			return r >= 0
+		case "<":
+			return r < 0
+		case "<=":
+			return r <= 0
+		}
+		return false
+	}
+	in := c.inPeriod(v)
+	switch c.op {
+	case "=":
+		return in
+	case "!=":
+		return !in
+	case ">":
+		return !in && v.After(c.version)
+	case ">=":
+		return in || v.After(c.version)
+	case "~":
+		return in && !v.Before(c.version)
+	case "<":
+		return !in && v.Before(c.version)
+	case "<=":
+		return in || v.Before(c.version)
+	}
+	return false
+}
+
+// inPeriod reports whether the leading part of v is the same as the leading part of the version of the condition.
+func (c condition) inPeriod(v *Calver) bool {
+	return renderWith(v, c.period) == renderWith(c.version, c.period)
+}
+
+func renderWith(cv *Calver, layout []token) string {
+	ncv := cv.clone()
+	ncv.layout = layout
+	ncv.trimSuffix = false
+	return ncv.String()
+}
+
+// trimLastSegment returns layout without the last version/time token (and the trailing modifier).
+// Optional segments are treated as present.
+func trimLastSegment(layout []token) []token {
+	layout = slices.DeleteFunc(slices.Clone(layout), func(t token) bool {
+		_, ok := t.(tokenOptional)
+		return ok
+	})
+	i := len(layout) - 1
+	for ; i >= 0; i-- {
+		if _, ok := layout[i].(tokenSep); ok {
+			continue
+		}
+		if layout[i].token() == tMODIFIER.token() {
+			continue
+		}
+		break
+	}
+	if i < 0 {
+		return []token{}
+	}
+	return layout[:i]
+}
@@ -0,0 +1,114 @@
+package calver
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		layout  string
+		expr    string
+		version string
+		want    bool
+	}{
+		{"YY.0M.MICRO", ">=24.01.0, <25.01.0", "24.10.3", true},
+		{"YY.0M.MICRO", ">=24.01.0, <25.01.0", "25.01.0", false},
+		{"YY.0M.MICRO", ">=24.01.0, <25.01.0", "23.12.9", false},
+		{"YY.0M.MICRO", "24.10.3", "24.10.3", true},
+		{"YY.0M.MICRO", "=24.10.3", "24.10.2", false},
+		{"YY.0M.MICRO", "!=24.10.3", "24.10.2", true},
+		{"YY.0M.MICRO", ">24.10.3", "24.10.3", false},
+		{"YY.0M.MICRO", "<=24.10.3", "24.10.3", true},
+		{"YY.0M.MICRO", "~24.10", "24.10.0", true},
+		{"YY.0M.MICRO", "~24.10", "24.10.12", true},
+		{"YY.0M.MICRO", "~24.10", "24.11.0", false},
+		{"YY.0M.MICRO", "~24.10", "24.09.5", false},
+		{"YY.0M.MICRO", "~24.10.2", "24.10.3", true},
+		{"YY.0M.MICRO", "~24.10.2", "24.10.1", false},
+		{"YY.0M.MICRO", "~24.10.2", "24.11.0", false},
+		{"YY.0M.MICRO", "24.10.*", "24.10.7", true},
+		{"YY.0M.MICRO", "24.10.x", "24.11.7", false},
+		{"YY.0M.MICRO", "!=24.10.*", "24.11.7", true},
+		{"YY.0M.MICRO", "24.*", "24.11.7", true},
+		{"YY.0M.MICRO", "24.10.* || 24.12.*", "24.12.1", true},
+		{"YY.0M.MICRO", "24.10.* || 24.12.*", "24.11.1", false},
+		{"YY.0M.MICRO", ">24.10", "24.10.9", false},
+		{"YY.0M.MICRO", ">24.10", "24.11.0", true},
+		{"YY.0M.MICRO", "<=24.10", "24.10.9", true},
+		{"YY.0M.MICRO", "<24.10", "24.10.0", false},
+		{"YY.0M.MICRO", ">= 24.10", "24.10.0", true},
+		{"YY.0M.MICRO-MODIFIER", "~24.10", "24.10.0-rc", true},
+		{"YY.0M.MICRO-MODIFIER", ">=24.10.0-", "24.10.0-rc", false},
+		{"YYYY.0M.0D", ">=2024.10.01, <2024.11.01", "2024.10.31", true},
+		{"MAJOR.MINOR.MICRO", "~1.2", "1.2.9", true},
+		{"MAJOR.MINOR.MICRO", ">=1.2.0, <2.0.0", "2.0.0", false},
+		{"YY.0M[.MICRO]", "~24.10", "24.10.3", true},
+		{"YY.0M[.MICRO]", "~24.10", "24.10", true},
+		{"YY.0M[.MICRO]", "~24.10", "24.11.0", false},
+		{"YY.0M[.MICRO]", "~24.10.2", "24.10.3", true},
+		{"YY.0M[.MICRO]", "~24.10.2", "24.10.1", false},
+		{"YY.0M[.MICRO][-MODIFIER]", "~24.10", "24.10.1-rc", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s/%s", tt.layout, tt.expr, tt.version), func(t *testing.T) {
+			c, err := ParseConstraint(tt.layout, tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err := Parse(tt.layout, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Check(v); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraintError(t *testing.T) {
+	tests := []struct {
+		layout string
+		expr   string
+	}{
+		{"YY.0M.MICRO", ""},
+		{"YY.0M.MICRO", ">=24.01.0,"},
+		{"YY.0M.MICRO", "invalid"},
+		{"YY.0M.MICRO", ">=24.10.*"},
+		{"YY.0M.MICRO", "24.1"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.expr), func(t *testing.T) {
+			if _, err := ParseConstraint(tt.layout, tt.expr); err == nil {
+				t.Error("want error")
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	layout := "YY.0M.MICRO"
+	cvs := Calvers{}
+	for _, v := range []string{"24.09.1", "24.10.0", "24.10.1", "24.11.0"} {
+		cv, err := Parse(layout, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cvs = append(cvs, cv)
+	}
+	c, err := ParseConstraint(layout, "~24.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := cvs.Filter(c)
+	want := []string{"24.10.0", "24.10.1"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("got %v\nwant %v", got[i].String(), want[i])
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package calver
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ConvertTo returns *Calver converted to the layout.
+// The time, the versions, the modifier, the metadata and the values of custom tokens are carried over.
+// An error is returned if information of the version would be lost by the conversion
+// (e.g. converting '24.10.1.3' in 'YY.0M.MINOR.MICRO' to 'YYYY.0M.MICRO' drops MINOR 1).
+func (cv *Calver) ConvertTo(layout string) (*Calver, error) {
+	available := slices.Clone(builtinTokens)
+	for _, t := range cv.layout {
+		if tc, ok := t.(tokenCustom); ok {
+			available = append(available, tc)
+		}
+	}
+	tokens, err := tokenizeLayoutWith(layout, available)
+	if err != nil {
+		return nil, err
+	}
+	ncv := cv.clone()
+	ncv.layout = tokens
+
+	// Check that the version is restored from the converted version string.
+	tmpl := ncv.clone()
+	tmpl.major, tmpl.minor, tmpl.micro = 0, 0, 0
+	tmpl.modifier, tmpl.metadata = "", ""
+	tmpl.values = nil
+	back, err := tmpl.Parse(ncv.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert '%s' to layout '%s': %w", cv.String(), layout, err)
+	}
+	for _, t := range cv.layout {
+		if _, ok := t.(tokenSep); ok {
+			continue
+		}
+		want := renderWith(cv, []token{t})
+		got := renderWith(back, []token{t})
+		if got != want {
+			return nil, fmt.Errorf("failed to convert '%s' to layout '%s': the value '%s' of token '%s' would be lost", cv.String(), layout, want, t.token())
+		}
+	}
+	return ncv, nil
+}
@@ -0,0 +1,70 @@
+package calver
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestConvertTo(t *testing.T) {
+	tests := []struct {
+		from    string
+		version string
+		to      string
+		want    string
+		wantErr bool
+	}{
+		{"YY.MM.MICRO", "24.10.3", "YYYY.0M.MICRO", "2024.10.3", false},
+		{"YYYY.0M.MICRO", "2024.10.3", "YY.MM.MICRO", "24.10.3", false},
+		{"YY.0M.MICRO-MODIFIER", "24.10.3-rc.1", "YYYY.0M.MICRO-MODIFIER", "2024.10.3-rc.1", false},
+		{"YY.0M.MINOR.MICRO", "24.10.0.3", "YYYY.0M.MICRO", "2024.10.3", false},
+		{"YY.0M.MINOR.MICRO", "24.10.1.3", "YYYY.0M.MICRO", "", true},
+		{"YYYY.0M.0D", "2024.10.15", "YYYY.0M", "", true},
+		{"YYYY.0M.0D", "2024.10.15", "YYYY.DDD", "2024.289", false},
+		{"YY.0M.MICRO-MODIFIER", "24.10.3-rc.1", "YYYY.0M.MICRO", "", true},
+		{"YY.0M.MICRO", "24.10.3", "YYYY.0M.0D.MICRO", "2024.10.01.3", false},
+		{"YY.0M.MICRO", "24.10.3", "YYYY.YY.MICRO", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.version, tt.to), func(t *testing.T) {
+			cv, err := Parse(tt.from, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.ConvertTo(tt.to)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v\nwant %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertToCustom(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(TokenDef{Name: "BUILD", Counter: true}); err != nil {
+		t.Fatal(err)
+	}
+	cv, err := r.Parse("YY.0M.BUILD", "24.10.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cv.ConvertTo("YYYY.0M.0D.BUILD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2024.10.01.7"; got.String() != want {
+		t.Errorf("got %v\nwant %v", got.String(), want)
+	}
+	if _, err := cv.ConvertTo("YYYY.0M"); err == nil {
+		t.Error("want error")
+	}
+}
@@ -0,0 +1,231 @@
+package calver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ErrOverflow is reported when a value does not fit in the budget of the encoding scheme.
+var ErrOverflow = errors.New("overflow")
+
+// EncodeField is a numeric token of the layout and its budget in an encoded integer.
+type EncodeField struct {
+	// Token is the name of the token (e.g. "YY", "0M", "MICRO").
+	Token string
+	// Digits is the number of decimal digits for the value of the token.
+	Digits int
+	// Bits is the number of bits for the value of the token. Either Digits or Bits must be set.
+	Bits int
+}
+
+// EncodeScheme is the fields packed into an integer, from the most significant one.
+// For example, {{"YY", 2, 0}, {"0M", 2, 0}, {"MICRO", 3, 0}} encodes 24.05.1 as 2405001.
+type EncodeScheme []EncodeField
+
+// EncodeInt returns the integer (e.g. Android versionCode) encoded from the version using the scheme.
+// The scheme must contain all the numeric tokens of the layout in the order of the layout so that the integer increases monotonically with the version.
+// MODIFIER can not be encoded. METADATA is ignored.
+func (cv *Calver) EncodeInt(scheme EncodeScheme) (int64, error) {
+	if err := cv.checkScheme(scheme); err != nil {
+		return 0, err
+	}
+	n, err := cv.encodeFields(scheme, math.MaxInt64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(n), nil
+}
+
+// EncodeQuad returns the four 16-bit integers (e.g. Windows FILEVERSION) encoded from the version using the schemes of each part.
+// For example, {{{"YYYY", 4, 0}}, {{"0M", 2, 0}, {"0D", 2, 0}}, {{"MICRO", 5, 0}}, {}} encodes 2024.05.07.3 as 2024,507,3,0.
+// An empty scheme encodes the part as 0.
+func (cv *Calver) EncodeQuad(scheme [4]EncodeScheme) ([4]uint16, error) {
+	var q [4]uint16
+	if err := cv.checkScheme(flattenQuad(scheme)); err != nil {
+		return q, err
+	}
+	for i, s := range scheme {
+		n, err := cv.encodeFields(s, math.MaxUint16)
+		if err != nil {
+			return q, err
+		}
+		q[i] = uint16(n)
+	}
+	return q, nil
+}
+
+// DecodeInt returns *Calver decoded from the integer using the layout and the scheme.
+// The decoding is the inverse of EncodeInt.
+func DecodeInt(layout string, scheme EncodeScheme, n int64) (*Calver, error) {
+	cv, err := New(layout)
+	if err != nil {
+		return nil, err
+	}
+	return cv.DecodeInt(scheme, n)
+}
+
+// DecodeInt returns *Calver decoded from the integer using the layout of cv and the scheme.
+func (cv *Calver) DecodeInt(scheme EncodeScheme, n int64) (*Calver, error) {
+	if err := cv.checkScheme(scheme); err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("invalid encoded version: %d", n)
+	}
+	values := map[string]string{}
+	if err := decodeFields(scheme, uint64(n), values); err != nil {
+		return nil, err
+	}
+	ncv, err := cv.parseValues(values, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("encoded version %d can not be decoded using layout '%s': %w", n, cv.Layout(), err)
+	}
+	return ncv, nil
+}
+
+// DecodeQuad returns *Calver decoded from the four 16-bit integers using the layout and the schemes of each part.
+// The decoding is the inverse of EncodeQuad.
+func DecodeQuad(layout string, scheme [4]EncodeScheme, q [4]uint16) (*Calver, error) {
+	cv, err := New(layout)
+	if err != nil {
+		return nil, err
+	}
+	return cv.DecodeQuad(scheme, q)
+}
+
+// DecodeQuad returns *Calver decoded from the four 16-bit integers using the layout of cv and the schemes of each part.
+func (cv *Calver) DecodeQuad(scheme [4]EncodeScheme, q [4]uint16) (*Calver, error) {
+	if err := cv.checkScheme(flattenQuad(scheme)); err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for i, s := range scheme {
+		if err := decodeFields(s, uint64(q[i]), values); err != nil {
+			return nil, err
+		}
+	}
+	ncv, err := cv.parseValues(values, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("encoded version %d,%d,%d,%d can not be decoded using layout '%s': %w", q[0], q[1], q[2], q[3], cv.Layout(), err)
+	}
+	return ncv, nil
+}
+
+// checkScheme checks that the fields contain each numeric token of the layout exactly once, in the order of the layout.
+func (cv *Calver) checkScheme(fields []EncodeField) error {
+	nums, err := numericTokens(cv.layout)
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, f := range fields {
+		if _, err := f.radix(); err != nil {
+			return err
+		}
+		found := false
+		for _, t := range nums {
+			if t.token() == f.Token {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no numeric token '%s' in the layout '%s'", f.Token, cv.Layout())
+		}
+		if seen[f.Token] {
+			return fmt.Errorf("token '%s' is duplicated in the scheme", f.Token)
+		}
+		seen[f.Token] = true
+	}
+	for _, t := range nums {
+		if !seen[t.token()] {
+			return fmt.Errorf("no token '%s' in the scheme", t.token())
+		}
+	}
+	// The encoded integer increases monotonically only with the fields in the order of precedence
+	for i, t := range nums {
+		if fields[i].Token != t.token() {
+			return fmt.Errorf("token '%s' is out of the order of the layout '%s' in the scheme", fields[i].Token, cv.Layout())
+		}
+	}
+	return nil
+}
+
+// encodeFields packs the values of the fields into an integer up to limit.
+func (cv *Calver) encodeFields(fields []EncodeField, limit uint64) (uint64, error) {
+	if cv.modifier != "" {
+		return 0, fmt.Errorf("modifier '%s' can not be encoded", cv.modifier)
+	}
+	var n uint64
+	for _, f := range fields {
+		r, _ := f.radix()
+		var t token
+		for _, tt := range cv.layout {
+			if tt.token() == f.Token {
+				t = tt
+				break
+			}
+		}
+		s := semverValue(cv, t)
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value '%s' of token '%s' is not numeric", s, f.Token)
+		}
+		if v >= r {
+			return 0, fmt.Errorf("%w: value %d of token '%s' exceeds %s", ErrOverflow, v, f.Token, f.budget())
+		}
+		if v > limit || n > (limit-v)/r {
+			return 0, fmt.Errorf("%w: encoded version exceeds %d", ErrOverflow, limit)
+		}
+		n = n*r + v
+	}
+	return n, nil
+}
+
+// decodeFields unpacks the integer into the values of the fields.
+func decodeFields(fields []EncodeField, n uint64, values map[string]string) error {
+	orig := n
+	for i := len(fields) - 1; i >= 0; i-- {
+		r, _ := fields[i].radix()
+		values[fields[i].Token] = strconv.FormatUint(n%r, 10)
+		n /= r
+	}
+	if n != 0 {
+		return fmt.Errorf("%w: encoded value %d exceeds the scheme", ErrOverflow, orig)
+	}
+	return nil
+}
+
+func flattenQuad(scheme [4]EncodeScheme) []EncodeField {
+	fields := []EncodeField{}
+	for _, s := range scheme {
+		fields = append(fields, s...)
+	}
+	return fields
+}
+
+// radix returns the number of the possible values of the field.
+func (f EncodeField) radix() (uint64, error) {
+	switch {
+	case f.Digits > 0 && f.Bits > 0:
+		return 0, fmt.Errorf("both digits and bits are set for token '%s'", f.Token)
+	case f.Digits > 0 && f.Digits <= 19:
+		r := uint64(1)
+		for range f.Digits {
+			r *= 10
+		}
+		return r, nil
+	case f.Bits > 0 && f.Bits <= 63:
+		return 1 << f.Bits, nil
+	}
+	return 0, fmt.Errorf("invalid budget of token '%s': digits %d, bits %d", f.Token, f.Digits, f.Bits)
+}
+
+func (f EncodeField) budget() string {
+	if f.Digits > 0 {
+		return fmt.Sprintf("%d digits", f.Digits)
+	}
+	return fmt.Sprintf("%d bits", f.Bits)
+}
@@ -0,0 +1,252 @@
+package calver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var androidScheme = EncodeScheme{{Token: "YY", Digits: 2}, {Token: "0M", Digits: 2}, {Token: "MICRO", Digits: 3}}
+
+var windowsScheme = [4]EncodeScheme{
+	{{Token: "YYYY", Digits: 4}},
+	{{Token: "0M", Digits: 2}, {Token: "0D", Digits: 2}},
+	{{Token: "MICRO", Bits: 16}},
+	{},
+}
+
+func TestEncodeInt(t *testing.T) {
+	tests := []struct {
+		layout       string
+		version      string
+		scheme       EncodeScheme
+		want         int64
+		wantErr      bool
+		wantOverflow bool
+	}{
+		{"YY.0M.MICRO", "24.05.1", androidScheme, 2405001, false, false},
+		{"YY.0M.MICRO", "24.10.999", androidScheme, 2410999, false, false},
+		{"YY.0M.MICRO", "24.10.1000", androidScheme, 0, true, true},
+		{"YY.0M.MICRO[+METADATA]", "24.05.1+build.5", androidScheme, 2405001, false, false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.1-rc.1", androidScheme, 0, true, false},
+		{"YY.0M.MICRO", "24.05.1", EncodeScheme{{Token: "YY", Bits: 7}, {Token: "0M", Bits: 4}, {Token: "MICRO", Bits: 8}}, 24<<12 | 5<<8 | 1, false, false},
+		{"YY.0M.MICRO", "24.05.1", EncodeScheme{{Token: "YY", Digits: 2}, {Token: "MICRO", Digits: 3}}, 0, true, false},
+		{"YY.0M.MICRO", "24.05.1", EncodeScheme{{Token: "MICRO", Digits: 3}, {Token: "YY", Digits: 2}, {Token: "0M", Digits: 2}}, 0, true, false},
+		{"YY.0M.MICRO", "24.05.1", EncodeScheme{{Token: "0M", Digits: 2}, {Token: "YY", Digits: 2}, {Token: "MICRO", Digits: 3}}, 0, true, false},
+		{"YY.0M.MICRO", "24.05.1", EncodeScheme{{Token: "YY", Digits: 2}, {Token: "0M", Digits: 2}, {Token: "MICRO", Digits: 3}, {Token: "0D", Digits: 2}}, 0, true, false},
+		{"YY.0M.MICRO", "24.05.1", EncodeScheme{{Token: "YY", Digits: 2}, {Token: "0M", Digits: 2, Bits: 4}, {Token: "MICRO", Digits: 3}}, 0, true, false},
+		{"YY.0M.MICRO", "24.05.1", EncodeScheme{{Token: "YY", Digits: 2}, {Token: "0M", Digits: 17}, {Token: "MICRO", Digits: 3}}, 0, true, true},
+		{"MICRO", "9223372036854775807", EncodeScheme{{Token: "MICRO", Digits: 19}}, 9223372036854775807, false, false},
+		{"MAJOR.MICRO", "1.9223372036854775807", EncodeScheme{{Token: "MAJOR", Digits: 1}, {Token: "MICRO", Digits: 19}}, 0, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s/%v", tt.layout, tt.version, tt.scheme), func(t *testing.T) {
+			cv, err := Parse(tt.layout, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.EncodeInt(tt.scheme)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				if errors.Is(err, ErrOverflow) != tt.wantOverflow {
+					t.Errorf("got %v\nwant overflow %v", err, tt.wantOverflow)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+			back, err := cv.DecodeInt(tt.scheme, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !back.Equal(cv) {
+				t.Errorf("got %v\nwant %v", back, cv)
+			}
+		})
+	}
+}
+
+func TestDecodeInt(t *testing.T) {
+	tests := []struct {
+		layout  string
+		n       int64
+		want    string
+		wantErr bool
+	}{
+		{"YY.0M.MICRO", 2405001, "24.05.1", false},
+		{"YY.0M.MICRO", 2413001, "", true},
+		{"YY.0M.MICRO", 102405001, "", true},
+		{"YY.0M.MICRO", -1, "", true},
+		{"YY.MM.MICRO", 2405001, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d", tt.layout, tt.n), func(t *testing.T) {
+			got, err := DecodeInt(tt.layout, androidScheme, tt.n)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v\nwant %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeIntOrder(t *testing.T) {
+	// Ascending order
+	versions := []string{"24.05.1", "24.05.2", "24.05.10", "24.06.0", "24.10.0", "25.01.0"}
+	var prev int64
+	for _, v := range versions {
+		cv, err := Parse("YY.0M.MICRO", v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := cv.EncodeInt(androidScheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got <= prev {
+			t.Errorf("%v should be greater than %v", got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestEncodeQuad(t *testing.T) {
+	tests := []struct {
+		version      string
+		want         [4]uint16
+		wantErr      bool
+		wantOverflow bool
+	}{
+		{"2024.05.07.3", [4]uint16{2024, 507, 3, 0}, false, false},
+		{"2024.12.31.65535", [4]uint16{2024, 1231, 65535, 0}, false, false},
+		{"2024.12.31.65536", [4]uint16{}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			cv, err := Parse("YYYY.0M.0D.MICRO", tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.EncodeQuad(windowsScheme)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				if errors.Is(err, ErrOverflow) != tt.wantOverflow {
+					t.Errorf("got %v\nwant overflow %v", err, tt.wantOverflow)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+			back, err := DecodeQuad("YYYY.0M.0D.MICRO", windowsScheme, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back.String() != tt.version {
+				t.Errorf("got %v\nwant %v", back.String(), tt.version)
+			}
+		})
+	}
+}
+
+func TestEncodeQuadDigits(t *testing.T) {
+	scheme := [4]EncodeScheme{
+		{{Token: "YYYY", Digits: 4}},
+		{{Token: "0M", Digits: 2}, {Token: "0D", Digits: 2}},
+		{{Token: "MICRO", Digits: 5}},
+		{},
+	}
+	tests := []struct {
+		version string
+		want    [4]uint16
+		wantErr bool
+	}{
+		{"2024.12.31.65535", [4]uint16{2024, 1231, 65535, 0}, false},
+		{"2024.12.31.65536", [4]uint16{}, true},
+		{"2024.12.31.70000", [4]uint16{}, true},
+		{"2024.12.31.99999", [4]uint16{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			cv, err := Parse("YYYY.0M.0D.MICRO", tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.EncodeQuad(scheme)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				if !errors.Is(err, ErrOverflow) {
+					t.Errorf("got %v\nwant %v", err, ErrOverflow)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeQuadPartOverflow(t *testing.T) {
+	scheme := [4]EncodeScheme{
+		{{Token: "YYYY", Digits: 4}, {Token: "0M", Digits: 2}},
+		{{Token: "MICRO", Digits: 4}},
+	}
+	cv, err := Parse("YYYY.0M.MICRO", "2024.05.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cv.EncodeQuad(scheme); !errors.Is(err, ErrOverflow) {
+		t.Errorf("got %v\nwant %v", err, ErrOverflow)
+	}
+	if _, err := DecodeQuad("YYYY.0M.MICRO", scheme, [4]uint16{65535, 10000, 0, 0}); !errors.Is(err, ErrOverflow) {
+		t.Errorf("got %v\nwant %v", err, ErrOverflow)
+	}
+}
+
+func TestEncodeQuadOrder(t *testing.T) {
+	scheme := [4]EncodeScheme{
+		{{Token: "MICRO", Bits: 16}},
+		{{Token: "YYYY", Digits: 4}},
+		{{Token: "0M", Digits: 2}, {Token: "0D", Digits: 2}},
+		{},
+	}
+	cv, err := Parse("YYYY.0M.0D.MICRO", "2024.05.07.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cv.EncodeQuad(scheme); err == nil {
+		t.Error("want error")
+	}
+	if _, err := DecodeQuad("YYYY.0M.0D.MICRO", scheme, [4]uint16{3, 2024, 507, 0}); err == nil {
+		t.Error("want error")
+	}
+}
@@ -0,0 +1,39 @@
+package calver
+
+import "fmt"
+
+// RangeError is an error that the value of the token is out of range.
+type RangeError struct {
+	Token string
+	Value int
+	Min   int
+	Max   int
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("value %d of token '%s' is out of range (%d-%d)", e.Value, e.Token, e.Min, e.Max)
+}
+
+// ParseError is an error that the version string could not be parsed using the layout.
+type ParseError struct {
+	// Input is the version string.
+	Input string
+	// Layout is the layout.
+	Layout string
+	// Offset is the byte offset of the input where parsing failed.
+	Offset int
+	// Token is the expected token at Offset. If empty, the end of the input is expected.
+	Token string
+	// Remaining is the text of the input from Offset.
+	Remaining string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse '%s' using layout '%s': %v", e.Input, e.Layout, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,51 @@
+package calver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		layout        string
+		value         string
+		wantOffset    int
+		wantToken     string
+		wantRemaining string
+	}{
+		{"YYYY.0M.MICRO", "2024.1x.3", 5, "0M", "1x.3"},
+		{"YYYY.MM.MICRO", "2024.1x.3", 6, ".", "x.3"},
+		{"YYYY.0M.MICRO", "2024.10.x", 8, "MICRO", "x"},
+		{"YYYY.0M.MICRO", "2024.10.3.1", 9, "", ".1"},
+		{"YYYY.0M.0D", "2024.02.30", 8, "0D", "30"},
+		{"YY.0M.MICRO-MODIFIER", "24.10.3+rc", 7, "-", "+rc"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.value), func(t *testing.T) {
+			_, err := Parse(tt.layout, tt.value)
+			if err == nil {
+				t.Fatal("want error")
+			}
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("got %T\nwant %T", err, perr)
+			}
+			if perr.Input != tt.value {
+				t.Errorf("got %v\nwant %v", perr.Input, tt.value)
+			}
+			if perr.Layout != tt.layout {
+				t.Errorf("got %v\nwant %v", perr.Layout, tt.layout)
+			}
+			if perr.Offset != tt.wantOffset {
+				t.Errorf("got %v\nwant %v", perr.Offset, tt.wantOffset)
+			}
+			if perr.Token != tt.wantToken {
+				t.Errorf("got %v\nwant %v", perr.Token, tt.wantToken)
+			}
+			if perr.Remaining != tt.wantRemaining {
+				t.Errorf("got %v\nwant %v", perr.Remaining, tt.wantRemaining)
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package calver
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// inferCosts is the plausibility cost of the tokens used to rank inferred layouts with the same number of round-tripped samples.
+// Lower is more plausible.
+var inferCosts = map[string]int{
+	tYYYY.token():  0,
+	tYY.token():    0,
+	t0Y.token():    1,
+	tGGGG.token():  3,
+	tGG.token():    3,
+	t0G.token():    3,
+	tQ.token():     4,
+	t0Q.token():    4,
+	tMM.token():    0,
+	t0M.token():    0,
+	tWW.token():    3,
+	t0W.token():    2,
+	tDD.token():    3,
+	t0D.token():    1,
+	tDDD.token():   4,
+	t0DDD.token():  4,
+	tHH.token():    3,
+	t0H.token():    3,
+	tMI.token():    3,
+	t0MI.token():   3,
+	tEPOCH.token(): 0,
+	tMAJOR.token(): 1,
+	tMINOR.token(): 1,
+	tMICRO.token(): 1,
+}
+
+// inferRanks is the order of the calendar tokens in inferred layouts.
+// Calendar tokens in an inferred layout are in strictly ascending order of rank.
+var inferRanks = map[string]int{
+	tYYYY.token():  0,
+	tYY.token():    0,
+	t0Y.token():    0,
+	tGGGG.token():  0,
+	tGG.token():    0,
+	t0G.token():    0,
+	tQ.token():     1,
+	t0Q.token():    1,
+	tMM.token():    1,
+	t0M.token():    1,
+	tWW.token():    1,
+	t0W.token():    1,
+	tDDD.token():   1,
+	t0DDD.token():  1,
+	tDD.token():    2,
+	t0D.token():    2,
+	tHH.token():    3,
+	t0H.token():    3,
+	tMI.token():    4,
+	t0MI.token():   4,
+	tEPOCH.token(): 5,
+}
+
+// skeleton is the structure of a version string.
+type skeleton struct {
+	prefix   string
+	seps     []string
+	modSep   string
+	modifier bool
+	metadata bool
+	// optModifier and optMetadata are whether the modifier and the metadata are optional segments
+	optModifier bool
+	optMetadata bool
+}
+
+type inferred struct {
+	layout string
+	score  int
+	cost   int
+}
+
+// InferLayout returns candidate layouts of the version strings.
+// The candidates are built from the builtin tokens and ranked by the number of samples that are parsed and
+// round-tripped through String(), and then by the plausibility of the tokens (e.g. 'YY.0M.MICRO' is preferred to 'YY.0M.DD' for '24.10.1').
+func InferLayout(samples []string) ([]string, error) {
+	if len(samples) == 0 {
+		return nil, ErrNoVersions
+	}
+	groups := map[string][][]string{}
+	skeletons := map[string]skeleton{}
+	keys := []string{}
+	for _, s := range samples {
+		sk, values, ok := splitSample(s)
+		if !ok {
+			continue
+		}
+		key := sk.layout(nil)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+			skeletons[key] = sk
+		}
+		groups[key] = append(groups[key], values)
+	}
+	keys = append(keys, mergeSkeletons(keys, skeletons, groups)...)
+
+	candidates := map[string]*inferred{}
+	for _, key := range keys {
+		sk := skeletons[key]
+		for _, tokens := range inferTokens(groups[key]) {
+			l := sk.layout(tokens)
+			if _, ok := candidates[l]; ok {
+				continue
+			}
+			cv, err := New(l)
+			if err != nil {
+				continue
+			}
+			c := &inferred{layout: l, cost: inferCost(tokens, groups[key])}
+			if err := CheckLayout(l); err != nil {
+				c.cost += 3
+			}
+			for _, s := range samples {
+				pcv, err := cv.Parse(s)
+				if err != nil {
+					continue
+				}
+				if pcv.String() == s {
+					c.score++
+				}
+			}
+			if c.score > 0 {
+				candidates[l] = c
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("could not infer layout from the samples")
+	}
+	ranked := make([]*inferred, 0, len(candidates))
+	for _, c := range candidates {
+		ranked = append(ranked, c)
+	}
+	slices.SortFunc(ranked, func(a, b *inferred) int {
+		return cmp.Or(
+			cmp.Compare(b.score, a.score),
+			cmp.Compare(a.cost, b.cost),
+			strings.Compare(a.layout, b.layout),
+		)
+	})
+	layouts := make([]string, 0, len(ranked))
+	for _, c := range ranked {
+		layouts = append(layouts, c.layout)
+	}
+	return layouts, nil
+}
+
+// inferCost returns the plausibility cost of the tokens for the numeric values.
+func inferCost(tokens []token, values [][]string) int {
+	cost := 0
+	for i, t := range tokens {
+		cost += inferCosts[t.token()]
+		for _, v := range values {
+			// Single digit years (2000-2009) and short Unix times are rare
+			if (t.token() == tYY.token() && len(v[i]) == 1) || (t.token() == tEPOCH.token() && len(v[i]) < 9) {
+				cost += 4
+				break
+			}
+		}
+	}
+	return cost
+}
+
+// splitSample splits the version string into the skeleton and the numeric values.
+func splitSample(s string) (skeleton, []string, bool) {
+	sk := skeleton{}
+	values := []string{}
+	i := strings.IndexFunc(s, isDigit)
+	if i < 0 {
+		return sk, nil, false
+	}
+	sk.prefix = s[:i]
+	for {
+		j := i
+		for j < len(s) && isDigit(rune(s[j])) {
+			j++
+		}
+		values = append(values, s[i:j])
+		k := j
+		for k < len(s) && !isAlnum(rune(s[k])) && s[k] != '+' {
+			k++
+		}
+		if k == j || k == len(s) || !isDigit(rune(s[k])) {
+			i = j
+			break
+		}
+		sk.seps = append(sk.seps, s[j:k])
+		i = k
+	}
+	rest := s[i:]
+	if m, meta, ok := strings.Cut(rest, "+"); ok {
+		rest = m
+		sk.metadata = meta != ""
+		if !sk.metadata {
+			return sk, nil, false
+		}
+	}
+	if rest != "" {
+		k := strings.IndexFunc(rest, isAlnum)
+		if k < 0 {
+			return sk, nil, false
+		}
+		sk.modSep = rest[:k]
+		sk.modifier = true
+	}
+	return sk, values, true
+}
+
+// layout returns the layout string of the skeleton with the tokens.
+// If tokens is nil, '#' is used in place of the tokens.
+func (sk skeleton) layout(tokens []token) string {
+	var b strings.Builder
+	b.WriteString(quoteLiteral(sk.prefix))
+	for i := range len(sk.seps) + 1 {
+		if i > 0 {
+			b.WriteString(quoteLiteral(sk.seps[i-1]))
+		}
+		if tokens == nil {
+			b.WriteString("#")
+		} else {
+			b.WriteString(tokens[i].token())
+		}
+	}
+	if sk.modifier {
+		if sk.optModifier {
+			b.WriteString("[")
+		}
+		b.WriteString(quoteLiteral(sk.modSep))
+		b.WriteString(tMODIFIER.token())
+		if sk.optModifier {
+			b.WriteString("]")
+		}
+	}
+	if sk.metadata {
+		if sk.optMetadata {
+			b.WriteString("[")
+		}
+		b.WriteString("+")
+		b.WriteString(tMETADATA.token())
+		if sk.optMetadata {
+			b.WriteString("]")
+		}
+	}
+	return b.String()
+}
+
+// mergeSkeletons adds the skeletons of the samples with and without the modifier (or the metadata) merged into optional segments
+// (e.g. '24.10.1-rc.1' and '24.10.1' -> '#.#.#[-MODIFIER]'), and returns the keys of the added skeletons.
+func mergeSkeletons(keys []string, skeletons map[string]skeleton, groups map[string][][]string) []string {
+	bases := map[string][]string{}
+	order := []string{}
+	for _, key := range keys {
+		sk := skeletons[key]
+		base := skeleton{prefix: sk.prefix, seps: sk.seps}.layout(nil)
+		if _, ok := bases[base]; !ok {
+			order = append(order, base)
+		}
+		bases[base] = append(bases[base], key)
+	}
+	merged := []string{}
+	for _, base := range order {
+		if len(bases[base]) < 2 {
+			continue
+		}
+		m := skeleton{prefix: skeletons[bases[base][0]].prefix, seps: skeletons[bases[base][0]].seps}
+		values := [][]string{}
+		ok := true
+		for _, key := range bases[base] {
+			sk := skeletons[key]
+			switch {
+			case !sk.modifier:
+				m.optModifier = true
+			case m.modifier && m.modSep != sk.modSep:
+				// The modifiers with different separators can not be merged
+				ok = false
+			default:
+				m.modifier = true
+				m.modSep = sk.modSep
+			}
+			if sk.metadata {
+				m.metadata = true
+			} else {
+				m.optMetadata = true
+			}
+			values = append(values, groups[key]...)
+		}
+		m.optModifier = m.optModifier && m.modifier
+		m.optMetadata = m.optMetadata && m.metadata
+		if !ok || (!m.optModifier && !m.optMetadata) {
+			continue
+		}
+		key := m.layout(nil)
+		skeletons[key] = m
+		groups[key] = values
+		merged = append(merged, key)
+	}
+	return merged
+}
+
+// inferTokens returns the candidate tokens for the numeric values of the samples with the same skeleton.
+// Calendar tokens come first, and the rest of the values are MAJOR, MINOR and MICRO (e.g. YY.0M.MICRO, YYYY.MINOR.MICRO).
+func inferTokens(values [][]string) [][]token {
+	n := len(values[0])
+	matches := func(t token, i int) bool {
+		for _, v := range values {
+			p, rest, err := t.trimPrefix(v[i])
+			if err != nil || p != v[i] || rest != "" {
+				return false
+			}
+		}
+		return true
+	}
+	versions := []token{tMAJOR, tMINOR, tMICRO}
+	result := [][]token{}
+	var walk func(cal []token)
+	walk = func(cal []token) {
+		// The rest of the values are versions
+		if rest := n - len(cal); rest <= len(versions) {
+			ok := true
+			for i, t := range versions[len(versions)-rest:] {
+				if !matches(t, len(cal)+i) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				result = append(result, slices.Concat(cal, versions[len(versions)-rest:]))
+			}
+		}
+		if len(cal) == n {
+			return
+		}
+		rank := -1
+		if len(cal) > 0 {
+			rank = inferRanks[cal[len(cal)-1].token()]
+		}
+		for _, t := range builtinTokens {
+			r, ok := inferRanks[t.token()]
+			if !ok || r <= rank {
+				continue
+			}
+			switch {
+			case len(cal) == 0 && r != 0 && t.token() != tEPOCH.token():
+				// Calendar versions start with year
+				continue
+			case r == 2 && !contains(cal, tMM) && !contains(cal, t0M):
+				// Day of month follows month
+				continue
+			}
+			if !matches(t, len(cal)) {
+				continue
+			}
+			walk(append(slices.Clone(cal), t))
+		}
+	}
+	walk([]token{})
+	return result
+}
@@ -0,0 +1,64 @@
+package calver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestInferLayout(t *testing.T) {
+	tests := []struct {
+		samples []string
+		want    string
+		wantErr bool
+	}{
+		{[]string{"24.10.1"}, "YY.0M.MICRO", false},
+		{[]string{"24.10.1", "24.9.0"}, "YY.MM.MICRO", false},
+		{[]string{"2024.10.15", "2024.09.01"}, "YYYY.0M.0D", false},
+		{[]string{"2024.10.15", "2024.10.40"}, "YYYY.0M.MICRO", false},
+		{[]string{"2024.10.0.3"}, "YYYY.0M.MINOR.MICRO", false},
+		{[]string{"1.2.3"}, "MAJOR.MINOR.MICRO", false},
+		{[]string{"v24.10.1-rc.1", "v24.10.1-beta.2", "v24.10.0"}, "vYY.0M.MICRO[-MODIFIER]", false},
+		{[]string{"v24.10.1-rc.1", "v24.10.1-beta.2"}, "vYY.0M.MICRO-MODIFIER", false},
+		{[]string{"2024.10.15+build.1"}, "YYYY.0M.0D+METADATA", false},
+		{[]string{"1700000000"}, "EPOCH", false},
+		{[]string{"DD-2024.10.1"}, "'DD-'YYYY.0M.MICRO", false},
+		{[]string{"24.10.1-rc.1", "24.10.1"}, "YY.0M.MICRO[-MODIFIER]", false},
+		{[]string{"24.10.1-rc.1+build.1", "24.10.1-rc.2", "24.10.1"}, "YY.0M.MICRO[-MODIFIER][+METADATA]", false},
+		{[]string{"2024.10.15+build.1", "2024.10.15"}, "YYYY.0M.0D[+METADATA]", false},
+		// The example of calver detect in README.md
+		{[]string{"v1.1.2", "v1.1.1", "2023.05.1", "2023.05.0", "v1.1.0", "v1.0.1", "2023.03.1", "2023.03.0", "v1.0.0", "2023.02.0", "v0.1.0"}, "vMAJOR.MINOR.MICRO", false},
+		{[]string{"2023.05.1", "2023.05.0", "2023.03.1", "2023.03.0", "2023.02.0"}, "YYYY.0M.MICRO", false},
+		{[]string{"abc"}, "", true},
+		{[]string{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v", tt.samples), func(t *testing.T) {
+			got, err := InferLayout(tt.samples)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got[0] != tt.want {
+				t.Errorf("got %v\nwant %v", got[0], tt.want)
+			}
+			for _, l := range got {
+				if _, err := New(l); err != nil {
+					t.Errorf("invalid layout %v: %v", l, err)
+				}
+			}
+		})
+	}
+}
+
+func TestInferLayoutNoVersions(t *testing.T) {
+	if _, err := InferLayout(nil); !errors.Is(err, ErrNoVersions) {
+		t.Errorf("got %v\nwant %v", err, ErrNoVersions)
+	}
+}
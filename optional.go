@@ -0,0 +1,191 @@
+package calver
+
+import (
+	"errors"
+	"slices"
+)
+
+var (
+	tOptionalOpen  = tokenOptional{open: true}
+	tOptionalClose = tokenOptional{open: false}
+)
+
+var _ token = tokenOptional{}
+
+// tokenOptional is a bracket that encloses an optional segment of the layout (e.g. 'YYYY.0M[.MICRO]').
+// An optional segment is omitted when parsing if the version string does not have it,
+// and it is omitted when rendering if all of the version values in it are zero or empty
+// (calendar values are regarded as empty if they were absent in the parsed version string).
+type tokenOptional struct {
+	open bool
+}
+
+func (t tokenOptional) String() string {
+	return t.token()
+}
+
+func (t tokenOptional) token() string {
+	if t.open {
+		return "["
+	}
+	return "]"
+}
+
+func (t tokenOptional) trimPrefix(value string) (string, string, error) {
+	return "", value, nil
+}
+
+func (t tokenOptional) trimPrefixWithMaxLen(value string, maxLen int) (string, string, error) {
+	return "", value, nil
+}
+
+func (t tokenOptional) minLen() int {
+	return 0
+}
+
+// hasOptional returns true if the layout has optional segments.
+func hasOptional(layout []token) bool {
+	return slices.ContainsFunc(layout, func(t token) bool {
+		_, ok := t.(tokenOptional)
+		return ok
+	})
+}
+
+// checkOptional checks that the brackets of optional segments are balanced and not empty.
+func checkOptional(layout []token) error {
+	depth := 0
+	for i, t := range layout {
+		ot, ok := t.(tokenOptional)
+		if !ok {
+			continue
+		}
+		if ot.open {
+			depth++
+			continue
+		}
+		if depth == 0 {
+			return errors.New("unbalanced ']' in the layout")
+		}
+		if prev, ok := layout[i-1].(tokenOptional); ok && prev.open {
+			return errors.New("empty optional segment '[]' in the layout")
+		}
+		depth--
+	}
+	if depth > 0 {
+		return errors.New("unbalanced '[' in the layout")
+	}
+	return nil
+}
+
+// expandOptional returns all the layouts without brackets made by including or omitting each optional segment.
+// Layouts that include earlier segments come first (e.g. 'A[B][C]' -> 'ABC', 'AB', 'AC', 'A').
+func expandOptional(layout []token) [][]token {
+	variants, _ := expandOptionalFrom(layout, 0, false)
+	return variants
+}
+
+func expandOptionalFrom(layout []token, i int, nested bool) ([][]token, int) {
+	variants := [][]token{{}}
+	for i < len(layout) {
+		ot, ok := layout[i].(tokenOptional)
+		switch {
+		case ok && ot.open:
+			inner, j := expandOptionalFrom(layout, i+1, true)
+			inner = append(inner, []token{})
+			product := [][]token{}
+			for _, v := range variants {
+				for _, w := range inner {
+					product = append(product, slices.Concat(v, w))
+				}
+			}
+			variants = product
+			i = j + 1
+		case ok && nested:
+			return variants, i
+		case ok:
+			// Ignore the unbalanced close bracket (e.g. the leading part of the layout)
+			i++
+		default:
+			for j := range variants {
+				variants[j] = append(variants[j], layout[i])
+			}
+			i++
+		}
+	}
+	return variants, i
+}
+
+// resolveOptional returns the layout without brackets for rendering the version.
+// Optional segments whose version values are all zero or empty are omitted.
+func (cv *Calver) resolveOptional(layout []token) []token {
+	resolved, _ := cv.resolveOptionalFrom(layout, 0, false)
+	return resolved
+}
+
+func (cv *Calver) resolveOptionalFrom(layout []token, i int, nested bool) ([]token, int) {
+	resolved := []token{}
+	for i < len(layout) {
+		ot, ok := layout[i].(tokenOptional)
+		switch {
+		case ok && ot.open:
+			inner, j := cv.resolveOptionalFrom(layout, i+1, true)
+			if slices.ContainsFunc(inner, cv.hasValue) {
+				resolved = append(resolved, inner...)
+			}
+			i = j + 1
+		case ok && nested:
+			return resolved, i
+		case ok:
+			i++
+		default:
+			resolved = append(resolved, layout[i])
+			i++
+		}
+	}
+	return resolved, i
+}
+
+// hasValue returns true if the token has a value that is neither zero nor empty.
+// Calendar tokens have a value unless they were absent in the parsed version string.
+func (cv *Calver) hasValue(t token) bool {
+	var v string
+	switch tt := t.(type) {
+	case tokenCal:
+		return !slices.Contains(cv.absentCal, tt.token())
+	case tokenVer:
+		v = tt.verToString(cv)
+	case tokenCustom:
+		v = tt.render(cv)
+	default:
+		return false
+	}
+	return v != "0" && v != ""
+}
+
+// parseOptional parses version string trying each layout expanded from the optional segments.
+func (cv *Calver) parseOptional(value string) (*Calver, error) {
+	var first error
+	for _, l := range expandOptional(cv.layout) {
+		fcv := cv.clone()
+		fcv.layout = l
+		ncv, err := fcv.parse(value)
+		if err == nil {
+			ncv.layout = cv.layout
+			ncv.absentCal = nil
+			for _, t := range cv.layout {
+				if _, ok := t.(tokenCal); ok && !contains(l, t) {
+					ncv.absentCal = append(ncv.absentCal, t.token())
+				}
+			}
+			return ncv, nil
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	var perr *ParseError
+	if errors.As(first, &perr) {
+		perr.Layout = cv.Layout()
+	}
+	return nil, first
+}
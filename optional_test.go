@@ -0,0 +1,126 @@
+package calver
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestOptional(t *testing.T) {
+	tests := []struct {
+		layout  string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"YYYY.0M[.MICRO][-MODIFIER]", "2024.10", "2024.10", false},
+		{"YYYY.0M[.MICRO][-MODIFIER]", "2024.10.3", "2024.10.3", false},
+		{"YYYY.0M[.MICRO][-MODIFIER]", "2024.10-rc.1", "2024.10-rc.1", false},
+		{"YYYY.0M[.MICRO][-MODIFIER]", "2024.10.3-rc.1", "2024.10.3-rc.1", false},
+		{"YYYY.0M[.MICRO][-MODIFIER]", "2024.10.0", "2024.10", false},
+		{"YYYY.0M[.MICRO][-MODIFIER]", "2024.10.x", "", true},
+		{"YYYY[.MINOR].MICRO", "2024.3", "2024.3", false},
+		{"YYYY[.MINOR].MICRO", "2024.0.3", "2024.3", false},
+		{"YYYY[.MINOR].MICRO", "2024.1.3", "2024.1.3", false},
+		{"YYYY[.MINOR[.MICRO]]", "2024.0.3", "2024.0.3", false},
+		{"YYYY[.MINOR[.MICRO]]", "2024.1", "2024.1", false},
+		{"YYYY[.MINOR[.MICRO]]", "2024", "2024", false},
+		{"YYYY.0M[.0D]", "2024.10.15", "2024.10.15", false},
+		{"YYYY.0M[.0D]", "2024.10", "2024.10", false},
+		{"YYYY.0M[.0D]", "2024.10.01", "2024.10.01", false},
+		{"YYYY[.0M][.0D]", "2024.10", "2024.10", false},
+		{"YYYY[.0M[.0D]][.MICRO]", "2024.3", "2024.3", false},
+		{"YYYY[.0M[.0D]][.MICRO]", "2024.10.3", "2024.10.3", false},
+		{"YYYY[.0M[.0D]][.MICRO]", "2024.10.05.3", "2024.10.05.3", false},
+		{"[v]YY.0M.MICRO", "v24.10.1", "24.10.1", false},
+		{"[v]YY.0M.MICRO", "24.10.1", "24.10.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.value), func(t *testing.T) {
+			cv, err := Parse(tt.layout, tt.value)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", cv)
+				return
+			}
+			if cv.String() != tt.want {
+				t.Errorf("got %v\nwant %v", cv.String(), tt.want)
+			}
+			if cv.Layout() != tt.layout {
+				t.Errorf("got %v\nwant %v", cv.Layout(), tt.layout)
+			}
+		})
+	}
+}
+
+func TestOptionalCalendar(t *testing.T) {
+	cv, err := NewWithTime("YYYY.0M[.0D]", testtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2002.02.04"; cv.String() != want {
+		t.Errorf("got %v\nwant %v", cv.String(), want)
+	}
+	p, err := cv.Parse("2024.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2024.10"; p.String() != want {
+		t.Errorf("got %v\nwant %v", p.String(), want)
+	}
+	// The absence of the optional segment is not carried over to the next parse
+	p, err = p.Parse("2024.10.15")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2024.10.15"; p.String() != want {
+		t.Errorf("got %v\nwant %v", p.String(), want)
+	}
+}
+
+func TestOptionalNext(t *testing.T) {
+	cv, err := Parse("YY.0M[.MICRO]", "24.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cv.NextWithTime(cv.Time())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "24.10.1"; got.String() != want {
+		t.Errorf("got %v\nwant %v", got.String(), want)
+	}
+}
+
+func TestTokenizeLayoutOptional(t *testing.T) {
+	tests := []struct {
+		layout  string
+		wantErr bool
+	}{
+		{"YYYY.0M[.MICRO]", false},
+		{"YYYY[.MINOR[.MICRO]][-MODIFIER]", false},
+		{"YYYY.0M[.MICRO", true},
+		{"YYYY.0M.MICRO]", true},
+		{"YYYY.0M][.MICRO", true},
+		{"YYYY.0M[].MICRO", true},
+		{"YYYY[.MICRO][.MICRO]", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			_, err := New(tt.layout)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want error")
+			}
+		})
+	}
+}
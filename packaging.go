@@ -0,0 +1,117 @@
+package calver
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	debianUpstreamRe = regexp.MustCompile(`^[0-9][A-Za-z0-9.+~]*$`)
+	debianRevisionRe = regexp.MustCompile(`^[A-Za-z0-9.+~]+$`)
+	rpmRe            = regexp.MustCompile(`^[A-Za-z0-9._+~^]+$`)
+)
+
+// postChannels is the channels of modifiers for post-releases.
+var postChannels = []string{"post", "rev", "r"}
+
+// Debian returns the Debian version string ([epoch:]upstream[-revision]) of the version.
+//
+// The numeric tokens of the layout are the upstream version with leading zeros removed (e.g. '24.05.1' -> '24.5.1').
+// MODIFIER is mapped to a pre-release with '~' (e.g. 'rc.1' -> '~rc1') that sorts before the release,
+// or a post-release with '+' (e.g. 'post.1' -> '+post1') that sorts after the release.
+// The 'dev' channel sorts before the other pre-releases ('dev.1' -> '~~dev1').
+// METADATA is mapped to the Debian revision. If epoch is 0, it is omitted.
+// Unlike Compare, which ignores METADATA, dpkg orders versions by the revision (e.g. '24.05.1+1' < '24.05.1+2').
+func (cv *Calver) Debian(epoch int) (string, error) {
+	if epoch < 0 {
+		return "", fmt.Errorf("invalid epoch: %d", epoch)
+	}
+	upstream, err := cv.packageVersion("+")
+	if err != nil {
+		return "", err
+	}
+	if !debianUpstreamRe.MatchString(upstream) {
+		return "", fmt.Errorf("'%s' is not a valid Debian upstream version", upstream)
+	}
+	s := upstream
+	if epoch > 0 {
+		s = strconv.Itoa(epoch) + ":" + s
+	}
+	if cv.metadata != "" {
+		if !debianRevisionRe.MatchString(cv.metadata) {
+			return "", fmt.Errorf("metadata '%s' is not a valid Debian revision", cv.metadata)
+		}
+		s += "-" + cv.metadata
+	}
+	return s, nil
+}
+
+// RPM returns the RPM Version and Release of the version.
+//
+// The mapping of the Version is the same as Debian except that a post-release uses '^' (e.g. 'post.1' -> '^post1').
+// METADATA is mapped to the Release. If METADATA is empty, the Release is '1'.
+// Unlike Compare, which ignores METADATA, rpm orders versions by the Release.
+func (cv *Calver) RPM() (version, release string, err error) {
+	version, err = cv.packageVersion("^")
+	if err != nil {
+		return "", "", err
+	}
+	if !rpmRe.MatchString(version) {
+		return "", "", fmt.Errorf("'%s' is not a valid RPM version", version)
+	}
+	release = "1"
+	if cv.metadata != "" {
+		release = strings.NewReplacer("-", "_").Replace(cv.metadata)
+		if !rpmRe.MatchString(release) {
+			return "", "", fmt.Errorf("metadata '%s' is not a valid RPM release", cv.metadata)
+		}
+	}
+	return version, release, nil
+}
+
+// packageVersion returns the version string for OS packages with the separator of post-releases.
+func (cv *Calver) packageVersion(postSep string) (string, error) {
+	nums, err := numericTokens(cv.layout)
+	if err != nil {
+		return "", err
+	}
+	release := []string{}
+	for _, t := range nums {
+		v := semverValue(cv, t)
+		if !isNumeric(v) {
+			return "", fmt.Errorf("value '%s' of token '%s' is not numeric", v, t.token())
+		}
+		release = append(release, trimZeros(v))
+	}
+	s := strings.Join(release, ".")
+	if cv.modifier == "" {
+		return s, nil
+	}
+	m := strings.ToLower(strings.NewReplacer("-", ".", "_", ".").Replace(cv.modifier))
+	ids := splitIdentifiers(m)
+	for i := 0; i < len(ids); i++ {
+		first := i == 0
+		// 'rc' and '1' -> 'rc1'
+		group := ids[i]
+		if !isNumeric(group) && i+1 < len(ids) && isNumeric(ids[i+1]) {
+			group += trimZeros(ids[i+1])
+			i++
+		}
+		name := strings.TrimRight(group, "0123456789")
+		switch {
+		case first && slices.Contains(postChannels, name):
+			s += postSep + group
+		case first && name == "dev":
+			s += "~~" + group
+		case first, name == "dev":
+			// Pre-release, or development release of pre-release or post-release
+			s += "~" + group
+		default:
+			s += "." + group
+		}
+	}
+	return s, nil
+}
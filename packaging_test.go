@@ -0,0 +1,414 @@
+package calver
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// packagingVersions are versions in ascending order.
+var packagingVersions = []string{
+	"24.05.1-dev.1",
+	"24.05.1-alpha.1",
+	"24.05.1-alpha.2",
+	"24.05.1-alpha.10",
+	"24.05.1-beta.1",
+	"24.05.1-rc.1.dev.1",
+	"24.05.1-rc.1",
+	"24.05.1-rc.2",
+	"24.05.1",
+	"24.05.1-post.1.dev.1",
+	"24.05.1-post.1",
+	"24.05.2-rc.1",
+	"24.05.2",
+	"24.06.0",
+	"24.10.0",
+}
+
+func TestDebian(t *testing.T) {
+	tests := []struct {
+		layout  string
+		version string
+		epoch   int
+		want    string
+		wantErr bool
+	}{
+		{"YY.0M.MICRO", "24.05.1", 0, "24.5.1", false},
+		{"YY.0M.MICRO", "24.05.1", 1, "1:24.5.1", false},
+		{"YY.0M.MICRO", "24.05.1", -1, "", true},
+		{"YY.0M.MICRO[-MODIFIER]", "24.05.1-rc.1", 0, "24.5.1~rc1", false},
+		{"YY.0M.MICRO[-MODIFIER]", "24.05.1-rc1", 0, "24.5.1~rc1", false},
+		{"YY.0M.MICRO[-MODIFIER]", "24.05.1-dev.2", 0, "24.5.1~~dev2", false},
+		{"YY.0M.MICRO[-MODIFIER]", "24.05.1-rc.1.dev.2", 0, "24.5.1~rc1~dev2", false},
+		{"YY.0M.MICRO[-MODIFIER]", "24.05.1-post.1", 0, "24.5.1+post1", false},
+		{"YY.0M.MICRO[-MODIFIER][+METADATA]", "24.05.1-rc.1+1ubuntu1", 2, "2:24.5.1~rc1-1ubuntu1", false},
+		{"YY.0M.MICRO[-MODIFIER][+METADATA]", "24.05.1+build_1", 0, "", true},
+		{"YY.0M.MICRO[-MODIFIER]", "24.05.1-rc/1", 0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.version), func(t *testing.T) {
+			cv, err := Parse(tt.layout, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.Debian(tt.epoch)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRPM(t *testing.T) {
+	tests := []struct {
+		layout      string
+		version     string
+		wantVersion string
+		wantRelease string
+		wantErr     bool
+	}{
+		{"YY.0M.MICRO", "24.05.1", "24.5.1", "1", false},
+		{"YY.0M.MICRO[-MODIFIER]", "24.05.1-rc.1", "24.5.1~rc1", "1", false},
+		{"YY.0M.MICRO[-MODIFIER]", "24.05.1-post.1", "24.5.1^post1", "1", false},
+		{"YY.0M.MICRO[-MODIFIER][+METADATA]", "24.05.1-rc.1+2.el9", "24.5.1~rc1", "2.el9", false},
+		{"YY.0M.MICRO[-MODIFIER][+METADATA]", "24.05.1+build-1", "24.5.1", "build_1", false},
+		{"YY.0M.MICRO[-MODIFIER]", "24.05.1-rc/1", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.version), func(t *testing.T) {
+			cv, err := Parse(tt.layout, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotVersion, gotRelease, err := cv.RPM()
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v-%v", gotVersion, gotRelease)
+				return
+			}
+			if gotVersion != tt.wantVersion {
+				t.Errorf("got %v\nwant %v", gotVersion, tt.wantVersion)
+			}
+			if gotRelease != tt.wantRelease {
+				t.Errorf("got %v\nwant %v", gotRelease, tt.wantRelease)
+			}
+		})
+	}
+}
+
+func TestDebianOrder(t *testing.T) {
+	prev := ""
+	for _, v := range packagingVersions {
+		cv, err := Parse("YY.0M.MICRO[-MODIFIER]", v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := cv.Debian(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prev != "" && dpkgCompare(prev, got) >= 0 {
+			t.Errorf("%v should be less than %v", prev, got)
+		}
+		prev = got
+	}
+}
+
+func TestRPMOrder(t *testing.T) {
+	prev := ""
+	for _, v := range packagingVersions {
+		cv, err := Parse("YY.0M.MICRO[-MODIFIER]", v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _, err := cv.RPM()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prev != "" && rpmvercmp(prev, got) >= 0 {
+			t.Errorf("%v should be less than %v", prev, got)
+		}
+		prev = got
+	}
+}
+
+func TestPackagingMetadataOrder(t *testing.T) {
+	// METADATA is ignored by Compare, but ordered by dpkg and rpm
+	a, err := Parse("YY.0M.MICRO[+METADATA]", "24.05.1+2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Parse("YY.0M.MICRO[+METADATA]", "24.05.1+10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Equal(b) {
+		t.Errorf("%v and %v should be equal", a, b)
+	}
+	da, err := a.Debian(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := b.Debian(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dpkgCompare(da, db) >= 0 {
+		t.Errorf("%v should be less than %v", da, db)
+	}
+	va, ra, err := a.RPM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vb, rb, err := b.RPM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rpmvercmp(va, vb) != 0 || rpmvercmp(ra, rb) >= 0 {
+		t.Errorf("%v-%v should be less than %v-%v", va, ra, vb, rb)
+	}
+}
+
+func TestDpkgCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~~dev1", "1.0~alpha1", -1},
+		{"1.0+post1", "1.0.1", -1},
+		{"1:1.0", "2.0", 1},
+		{"1.0-1", "1.0-2", -1},
+		{"1.01", "1.1", 0},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.a, tt.b), func(t *testing.T) {
+			if got := dpkgCompare(tt.a, tt.b); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRpmvercmp(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0^post1", "1.0", 1},
+		{"1.0^post1", "1.0.1", -1},
+		{"1.0a", "1.0", 1},
+		{"1.0", "1.0a", -1},
+		{"1.01", "1.1", 0},
+		{"1.a", "1.1", -1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.a, tt.b), func(t *testing.T) {
+			if got := rpmvercmp(tt.a, tt.b); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// dpkgCompare compares Debian versions in the same way as 'dpkg --compare-versions'.
+func dpkgCompare(a, b string) int {
+	split := func(v string) (int, string, string) {
+		epoch := 0
+		if e, rest, ok := strings.Cut(v, ":"); ok {
+			fmt.Sscanf(e, "%d", &epoch) //nolint:errcheck
+			v = rest
+		}
+		rev := ""
+		if i := strings.LastIndex(v, "-"); i >= 0 {
+			v, rev = v[:i], v[i+1:]
+		}
+		return epoch, v, rev
+	}
+	ae, av, ar := split(a)
+	be, bv, br := split(b)
+	if c := cmp.Compare(ae, be); c != 0 {
+		return c
+	}
+	if c := dpkgVerrevcmp(av, bv); c != 0 {
+		return c
+	}
+	return dpkgVerrevcmp(ar, br)
+}
+
+func dpkgVerrevcmp(a, b string) int {
+	order := func(s string, i int) int {
+		if i >= len(s) {
+			return 0
+		}
+		c := s[i]
+		switch {
+		case isDigit(rune(c)):
+			return 0
+		case ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z'):
+			return int(c)
+		case c == '~':
+			return -1
+		}
+		return int(c) + 256
+	}
+	digit := func(s string, i int) bool {
+		return i < len(s) && isDigit(rune(s[i]))
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		firstDiff := 0
+		for (i < len(a) && !digit(a, i)) || (j < len(b) && !digit(b, j)) {
+			ac := order(a, i)
+			bc := order(b, j)
+			if ac != bc {
+				return cmp.Compare(ac, bc)
+			}
+			i++
+			j++
+		}
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		for digit(a, i) && digit(b, j) {
+			if firstDiff == 0 {
+				firstDiff = cmp.Compare(a[i], b[j])
+			}
+			i++
+			j++
+		}
+		if digit(a, i) {
+			return 1
+		}
+		if digit(b, j) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return firstDiff
+		}
+	}
+	return 0
+}
+
+// rpmvercmp compares RPM versions in the same way as rpmvercmp of rpm (4.15 or later).
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	alnum := func(s string, i int) bool {
+		return i < len(s) && isAlnum(rune(s[i]))
+	}
+	at := func(s string, i int) byte {
+		if i < len(s) {
+			return s[i]
+		}
+		return 0
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && !alnum(a, i) && a[i] != '~' && a[i] != '^' {
+			i++
+		}
+		for j < len(b) && !alnum(b, j) && b[j] != '~' && b[j] != '^' {
+			j++
+		}
+		// '~' sorts before everything
+		if at(a, i) == '~' || at(b, j) == '~' {
+			if at(a, i) != '~' {
+				return 1
+			}
+			if at(b, j) != '~' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+		// '^' sorts after the end, but before everything else
+		if at(a, i) == '^' || at(b, j) == '^' {
+			if i >= len(a) {
+				return -1
+			}
+			if j >= len(b) {
+				return 1
+			}
+			if at(a, i) != '^' {
+				return 1
+			}
+			if at(b, j) != '^' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+		if i >= len(a) || j >= len(b) {
+			break
+		}
+		si, sj := i, j
+		isnum := isDigit(rune(a[i]))
+		class := isAlpha
+		if isnum {
+			class = func(c byte) bool { return isDigit(rune(c)) }
+		}
+		for i < len(a) && class(a[i]) {
+			i++
+		}
+		for j < len(b) && class(b[j]) {
+			j++
+		}
+		if si == i {
+			return -1
+		}
+		if sj == j {
+			if isnum {
+				return 1
+			}
+			return -1
+		}
+		sa, sb := a[si:i], b[sj:j]
+		if isnum {
+			sa = strings.TrimLeft(sa, "0")
+			sb = strings.TrimLeft(sb, "0")
+			if c := cmp.Compare(len(sa), len(sb)); c != 0 {
+				return c
+			}
+		}
+		if c := strings.Compare(sa, sb); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case i >= len(a) && j >= len(b):
+		return 0
+	case i < len(a):
+		return 1
+	}
+	return -1
+}
+
+func isAlpha(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
@@ -0,0 +1,265 @@
+package calver
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	pep440Re      = regexp.MustCompile(`(?i)^v?(?:([0-9]+)!)?([0-9]+(?:\.[0-9]+)*)(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?([0-9]+)?)?(?:-([0-9]+)|[-_.]?(post|rev|r)[-_.]?([0-9]+)?)?(?:[-_.]?(dev)[-_.]?([0-9]+)?)?(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+	pep440LocalRe = regexp.MustCompile(`^[a-z0-9]+(?:\.[a-z0-9]+)*$`)
+)
+
+// pep440Channels maps the channels of modifiers to PEP 440 pre-release, post-release and development release.
+var pep440Channels = map[string]string{
+	"alpha":   "a",
+	"a":       "a",
+	"beta":    "b",
+	"b":       "b",
+	"rc":      "rc",
+	"c":       "rc",
+	"pre":     "rc",
+	"preview": "rc",
+	"post":    "post",
+	"rev":     "post",
+	"r":       "post",
+	"dev":     "dev",
+}
+
+// pep440Names is the names of PEP 440 channels used in modifiers mapped from PEP 440.
+var pep440Names = map[string]string{
+	"a":    "alpha",
+	"b":    "beta",
+	"rc":   "rc",
+	"post": "post",
+	"dev":  "dev",
+}
+
+// pep440Suffix is the pre-release, post-release and development release of PEP 440.
+type pep440Suffix struct {
+	pre     string // "a", "b" or "rc". Empty if not pre-release.
+	preN    int
+	post    bool
+	postN   int
+	dev     bool
+	devN    int
+	invalid bool
+}
+
+// PEP440 returns the PEP 440 string of the version.
+//
+// The numeric tokens of the layout are the release segments with leading zeros removed (e.g. '24.05.1' -> '24.5.1').
+// MODIFIER is mapped to the pre-release ('alpha.N', 'beta.N', 'rc.N' -> 'aN', 'bN', 'rcN'), the post-release ('post.N' -> '.postN')
+// and the development release ('dev.N' -> '.devN'). They can be combined in this order (e.g. 'rc.1.dev2' -> 'rc1.dev2').
+// METADATA is mapped to the local version label.
+func (cv *Calver) PEP440() (string, error) {
+	nums, err := numericTokens(cv.layout)
+	if err != nil {
+		return "", err
+	}
+	release := []string{}
+	for _, t := range nums {
+		v := semverValue(cv, t)
+		if !isNumeric(v) {
+			return "", fmt.Errorf("value '%s' of token '%s' is not numeric", v, t.token())
+		}
+		release = append(release, trimZeros(v))
+	}
+	s := strings.Join(release, ".")
+	if cv.modifier != "" {
+		suffix := parsePEP440Modifier(cv.modifier)
+		if suffix.invalid {
+			return "", fmt.Errorf("modifier '%s' can not be mapped to PEP 440", cv.modifier)
+		}
+		if suffix.pre != "" {
+			s += fmt.Sprintf("%s%d", suffix.pre, suffix.preN)
+		}
+		if suffix.post {
+			s += fmt.Sprintf(".post%d", suffix.postN)
+		}
+		if suffix.dev {
+			s += fmt.Sprintf(".dev%d", suffix.devN)
+		}
+	}
+	if cv.metadata != "" {
+		local := strings.ToLower(strings.NewReplacer("-", ".", "_", ".").Replace(cv.metadata))
+		if !pep440LocalRe.MatchString(local) {
+			return "", fmt.Errorf("metadata '%s' can not be mapped to PEP 440 local version label", cv.metadata)
+		}
+		s += "+" + local
+	}
+	return s, nil
+}
+
+// FromPEP440 returns *Calver mapped from the PEP 440 string using the layout.
+// The mapping is the inverse of PEP440. Non-normalized forms (e.g. '24.05.1-rc.1') are accepted.
+func FromPEP440(layout, s string) (*Calver, error) {
+	cv, err := New(layout)
+	if err != nil {
+		return nil, err
+	}
+	return cv.FromPEP440(s)
+}
+
+// FromPEP440 returns *Calver mapped from the PEP 440 string using the layout of cv.
+func (cv *Calver) FromPEP440(s string) (*Calver, error) {
+	m := pep440Re.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid PEP 440 version '%s'", s)
+	}
+	if m[1] != "" && trimZeros(m[1]) != "0" {
+		return nil, fmt.Errorf("PEP 440 version '%s' can not be mapped to layout '%s': epoch is not supported", s, cv.Layout())
+	}
+	nums, err := numericTokens(cv.layout)
+	if err != nil {
+		return nil, err
+	}
+	release := strings.Split(m[2], ".")
+	for _, r := range release[min(len(nums), len(release)):] {
+		if trimZeros(r) != "0" {
+			return nil, fmt.Errorf("PEP 440 version '%s' can not be mapped to layout '%s': the value '%s' would be lost", s, cv.Layout(), r)
+		}
+	}
+	values := map[string]string{}
+	for i, t := range nums {
+		if i < len(release) {
+			values[t.token()] = release[i]
+		}
+	}
+	mods := []string{}
+	if m[3] != "" {
+		mods = append(mods, pep440Names[pep440Channels[strings.ToLower(m[3])]], numOrZero(m[4]))
+	}
+	switch {
+	case m[5] != "":
+		mods = append(mods, pep440Names["post"], m[5])
+	case m[6] != "":
+		mods = append(mods, pep440Names["post"], numOrZero(m[7]))
+	}
+	if m[8] != "" {
+		mods = append(mods, pep440Names["dev"], numOrZero(m[9]))
+	}
+	modifier := strings.Join(mods, ".")
+	if modifier != "" && !contains(cv.layout, tMODIFIER) {
+		return nil, fmt.Errorf("no 'MODIFIER' in the layout '%s'", cv.Layout())
+	}
+	metadata := strings.ToLower(strings.NewReplacer("-", ".", "_", ".").Replace(m[10]))
+	if metadata != "" && !contains(cv.layout, tMETADATA) {
+		return nil, fmt.Errorf("no 'METADATA' in the layout '%s'", cv.Layout())
+	}
+	ncv, err := cv.parseValues(values, modifier, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("PEP 440 version '%s' can not be mapped to layout '%s': %w", s, cv.Layout(), err)
+	}
+	return ncv, nil
+}
+
+// PEP440Order returns *Calver that compares modifiers using PEP 440 ordering.
+// For the same release, development releases < pre-releases (a < b < rc) < the final release < post-releases.
+// Modifiers that can not be mapped to PEP 440 have lower precedence than the others and are compared lexically.
+func (cv *Calver) PEP440Order() *Calver {
+	ncv := cv.clone()
+	ncv.compareModifier = comparePEP440Modifier
+	ncv.modifierKey = pep440ModifierKey
+	ncv.modifierOrder = "pep440"
+	return ncv
+}
+
+func comparePEP440Modifier(a, b string) int {
+	as := parsePEP440Modifier(a)
+	bs := parsePEP440Modifier(b)
+	switch {
+	case as.invalid && bs.invalid:
+		return strings.Compare(a, b)
+	case as.invalid:
+		return -1
+	case bs.invalid:
+		return 1
+	}
+	ak := as.key()
+	bk := bs.key()
+	for i := range ak {
+		if c := cmp.Compare(ak[i], bk[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// key returns the sort key of the suffix (same as the packaging library of Python).
+func (s pep440Suffix) key() [4]int {
+	pre := map[string]int{"a": 0, "b": 1, "rc": 2}
+	var k [4]int
+	switch {
+	case s.pre == "" && !s.post && s.dev:
+		// X.devN is before all the pre-releases of X
+		k[0], k[1] = -1, 0
+	case s.pre == "":
+		k[0], k[1] = math.MaxInt, 0
+	default:
+		k[0], k[1] = pre[s.pre], s.preN
+	}
+	k[2] = -1
+	if s.post {
+		k[2] = s.postN
+	}
+	k[3] = math.MaxInt
+	if s.dev {
+		k[3] = s.devN
+	}
+	return k
+}
+
+// parsePEP440Modifier parses the modifier into the PEP 440 suffix.
+func parsePEP440Modifier(m string) pep440Suffix {
+	s := pep440Suffix{}
+	if m == "" {
+		return s
+	}
+	m = strings.ToLower(strings.NewReplacer("-", ".", "_", ".").Replace(m))
+	ids := splitIdentifiers(m)
+	phase := 0 // 1: pre, 2: post, 3: dev
+	for i := 0; i < len(ids); i++ {
+		ch, ok := pep440Channels[ids[i]]
+		if !ok {
+			return pep440Suffix{invalid: true}
+		}
+		n := 0
+		if i+1 < len(ids) && isNumeric(ids[i+1]) {
+			var err error
+			n, err = strconv.Atoi(ids[i+1])
+			if err != nil {
+				return pep440Suffix{invalid: true}
+			}
+			i++
+		}
+		var p int
+		switch ch {
+		case "post":
+			p = 2
+			s.post, s.postN = true, n
+		case "dev":
+			p = 3
+			s.dev, s.devN = true, n
+		default:
+			p = 1
+			s.pre, s.preN = ch, n
+		}
+		if p <= phase {
+			return pep440Suffix{invalid: true}
+		}
+		phase = p
+	}
+	return s
+}
+
+func numOrZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return trimZeros(s)
+}
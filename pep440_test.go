@@ -0,0 +1,136 @@
+package calver
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPEP440(t *testing.T) {
+	tests := []struct {
+		layout  string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{"YY.0M.MICRO", "24.05.1", "24.5.1", false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.1-rc1", "24.5.1rc1", false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.1-rc.1", "24.5.1rc1", false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.1-alpha.2", "24.5.1a2", false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.1-beta", "24.5.1b0", false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.1-dev.3", "24.5.1.dev3", false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.1-post.1", "24.5.1.post1", false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.1-rc.1.dev2", "24.5.1rc1.dev2", false},
+		{"YY.0M.MICRO-MODIFIER+METADATA", "24.05.1-rc.1+Build_5", "24.5.1rc1+build.5", false},
+		{"YYYY.0M.0D.MICRO", "2024.05.07.3", "2024.5.7.3", false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.1-hotfix", "", true},
+		{"YY.0M.MICRO-MODIFIER", "24.05.1-dev.1.rc.1", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.version), func(t *testing.T) {
+			cv, err := Parse(tt.layout, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.PEP440()
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+			back, err := FromPEP440(tt.layout, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := back.PEP440()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != got {
+				t.Errorf("got %v\nwant %v", s, got)
+			}
+		})
+	}
+}
+
+func TestFromPEP440(t *testing.T) {
+	tests := []struct {
+		layout  string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{"YY.0M.MICRO", "24.5.1", "24.05.1", false},
+		{"YY.0M.MICRO", "24.5", "24.05.0", false},
+		{"YY.0M.MICRO", "24.5.1.0", "24.05.1", false},
+		{"YY.0M.MICRO", "24.5.1.1", "", true},
+		{"YY.0M.MICRO-MODIFIER", "24.5.1rc1", "24.05.1-rc.1", false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.1-RC-1", "24.05.1-rc.1", false},
+		{"YY.0M.MICRO-MODIFIER", "24.5.1a1", "24.05.1-alpha.1", false},
+		{"YY.0M.MICRO-MODIFIER", "24.5.1.post2.dev1", "24.05.1-post.2.dev.1", false},
+		{"YY.0M.MICRO-MODIFIER", "24.5.1-3", "24.05.1-post.3", false},
+		{"YY.0M.MICRO", "24.5.1rc1", "", true},
+		{"YY.0M.MICRO", "1!24.5.1", "", true},
+		{"YY.0M.MICRO", "24.5.1-hotfix", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.version), func(t *testing.T) {
+			got, err := FromPEP440(tt.layout, tt.version)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v\nwant %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPEP440Order(t *testing.T) {
+	// Ascending order
+	versions := []string{
+		"24.05.1-dev.1",
+		"24.05.1-alpha.1.dev.1",
+		"24.05.1-alpha.1",
+		"24.05.1-beta.1",
+		"24.05.1-rc.1.dev.1",
+		"24.05.1-rc.1",
+		"24.05.1-rc.2",
+		"24.05.1",
+		"24.05.1-post.1.dev.1",
+		"24.05.1-post.1",
+		"24.05.2-dev.1",
+	}
+	cv, err := New("YY.0M.MICRO[-MODIFIER]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cv = cv.PEP440Order()
+	cvs := Calvers{}
+	for _, v := range versions {
+		pcv, err := cv.Parse(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cvs = append(cvs, pcv)
+	}
+	for i := range len(cvs) - 1 {
+		if !cvs[i].Before(cvs[i+1]) {
+			t.Errorf("%v should be before %v", cvs[i], cvs[i+1])
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package calver
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrereleaseOrder returns *Calver that compares modifiers using semver pre-release precedence.
+//
+// Modifiers are split into dot-separated identifiers (an identifier such as 'rc10' is split into 'rc' and '10').
+// Numeric identifiers are compared numerically and have lower precedence than non-numeric identifiers.
+// Non-numeric identifiers are compared in the order of channels (e.g. "dev", "alpha", "beta", "rc"),
+// identifiers not in channels have lower precedence than channels and are compared lexically.
+// A version without modifier always has higher precedence than a version with modifier.
+func (cv *Calver) PrereleaseOrder(channels ...string) *Calver {
+	ncv := cv.clone()
+	ncv.compareModifier = prereleaseComparator(channels)
+	ncv.modifierKey = prereleaseModifierKey(channels)
+	ncv.modifierOrder = fmt.Sprintf("prerelease%q", channels)
+	return ncv
+}
+
+// NextPrerelease returns next pre-release version *Calver of the channel at the current time of the clock.
+func (cv *Calver) NextPrerelease(channel string) (*Calver, error) {
+	return cv.NextPrereleaseWithTime(channel, cv.now())
+}
+
+// NextPrereleaseWithTime returns next pre-release version *Calver of the channel at the given time.
+//
+// If the modifier is a pre-release of the same channel, the numeric suffix of the modifier is incremented (e.g. 'rc.1' -> 'rc.2').
+// If the modifier is a pre-release of another channel, the modifier is switched to the channel starting at '.1' (e.g. 'beta.3' -> 'rc.1').
+// If the modifier is not set, the modifier of the next version is set to the channel starting at '.1'.
+func (cv *Calver) NextPrereleaseWithTime(channel string, now time.Time) (*Calver, error) {
+	if !contains(cv.layout, tMODIFIER) {
+		return nil, fmt.Errorf("no 'MODIFIER' in the layout '%s'", cv.Layout())
+	}
+	if channel == "" {
+		return nil, errors.New("empty pre-release channel")
+	}
+	if cv.modifier == "" {
+		ncv, err := cv.NextWithTime(now)
+		if err != nil {
+			return nil, err
+		}
+		ncv.modifier = withLeadingSep(channel, cv.modifierPrefix()) + ".1"
+		return ncv, nil
+	}
+	ncv := cv.clone()
+	ncv.metadata = "" // clear metadata
+	ch, sep, n, ok := splitPrereleaseCounter(cv.modifier)
+	switch {
+	case trimLeadingSep(ch) != trimLeadingSep(channel):
+		// Keep the leading separator of the current modifier
+		ncv.modifier = withLeadingSep(channel, strings.TrimSuffix(ch, trimLeadingSep(ch))) + ".1"
+	case ok:
+		ncv.modifier = ch + sep + strconv.Itoa(n+1)
+	default:
+		ncv.modifier = ch + ".1"
+	}
+	return ncv, nil
+}
+
+// Release returns *Calver without modifier.
+func (cv *Calver) Release() (*Calver, error) {
+	if !contains(cv.layout, tMODIFIER) {
+		return nil, fmt.Errorf("no 'MODIFIER' in the layout '%s'", cv.Layout())
+	}
+	ncv := cv.clone()
+	ncv.modifier = ""
+	return ncv, nil
+}
+
+// splitPrereleaseCounter splits modifier into the channel, the separator and the numeric suffix.
+func splitPrereleaseCounter(m string) (channel, sep string, n int, ok bool) {
+	i := len(m)
+	for i > 0 && isDigit(rune(m[i-1])) {
+		i--
+	}
+	if i == len(m) || i == 0 {
+		return m, "", 0, false
+	}
+	n, err := strconv.Atoi(m[i:])
+	if err != nil {
+		return m, "", 0, false
+	}
+	channel = m[:i]
+	if strings.HasSuffix(channel, ".") {
+		channel = strings.TrimSuffix(channel, ".")
+		sep = "."
+	}
+	return channel, sep, n, true
+}
+
+// modifierPrefix returns the leading separator of a new modifier.
+// The modifier without the separator in the layout has the leading separator '-'.
+func (cv *Calver) modifierPrefix() string {
+	for i, t := range cv.layout {
+		if t.token() == tMODIFIER.token() && i > 0 && !isSepToken(cv.layout[i-1]) {
+			return "-"
+		}
+	}
+	return ""
+}
+
+// withLeadingSep returns the channel with the leading separator unless the channel has one.
+func withLeadingSep(channel, sep string) string {
+	if trimLeadingSep(channel) != channel {
+		return channel
+	}
+	return sep + channel
+}
+
+func trimLeadingSep(m string) string {
+	return strings.TrimLeftFunc(m, func(r rune) bool {
+		return !isAlnum(r)
+	})
+}
+
+func prereleaseComparator(channels []string) func(a, b string) int {
+	channels = slices.Clone(channels)
+	return func(a, b string) int {
+		switch {
+		case a == b:
+			return 0
+		case a == "":
+			return 1
+		case b == "":
+			return -1
+		}
+		ai := splitIdentifiers(a)
+		bi := splitIdentifiers(b)
+		for i := 0; i < len(ai) && i < len(bi); i++ {
+			if c := compareIdentifier(ai[i], bi[i], channels); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(len(ai), len(bi))
+	}
+}
+
+// splitIdentifiers splits modifier into pre-release identifiers.
+func splitIdentifiers(m string) []string {
+	m = trimLeadingSep(m)
+	ids := []string{}
+	for _, id := range strings.Split(m, ".") {
+		// Split 'rc10' into 'rc' and '10'
+		i := len(id)
+		for i > 0 && isDigit(rune(id[i-1])) {
+			i--
+		}
+		if i > 0 && i < len(id) {
+			ids = append(ids, id[:i], id[i:])
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func compareIdentifier(a, b string, channels []string) int {
+	an := isNumeric(a)
+	bn := isNumeric(b)
+	switch {
+	case an && bn:
+		a = strings.TrimLeft(a, "0")
+		b = strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			return cmp.Compare(len(a), len(b))
+		}
+		return strings.Compare(a, b)
+	case an:
+		return -1
+	case bn:
+		return 1
+	}
+	ac := slices.Index(channels, a)
+	bc := slices.Index(channels, b)
+	switch {
+	case ac >= 0 && bc >= 0:
+		return cmp.Compare(ac, bc)
+	case ac >= 0:
+		return 1
+	case bc >= 0:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+func isAlnum(r rune) bool {
+	return isDigit(r) || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
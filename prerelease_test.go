@@ -0,0 +1,205 @@
+package calver
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPrereleaseOrder(t *testing.T) {
+	channels := []string{"dev", "alpha", "beta", "rc"}
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{"24.10.1-rc10", "24.10.1-rc9", 1},
+		{"24.10.1-rc.10", "24.10.1-rc.9", 1},
+		{"24.10.1-beta", "24.10.1-alpha", 1},
+		{"24.10.1-alpha", "24.10.1-dev", 1},
+		{"24.10.1-rc.1", "24.10.1-beta.5", 1},
+		{"24.10.1-", "24.10.1-rc.1", 1},
+		{"24.10.1-rc.1", "24.10.1-", -1},
+		{"24.10.1-rc", "24.10.1-rc.1", -1},
+		{"24.10.1-rc.1", "24.10.1-rc.1", 0},
+		{"24.10.1-1", "24.10.1-dev", -1},
+		{"24.10.1-snapshot", "24.10.1-dev", -1},
+		{"24.10.1-preview", "24.10.1-nightly", 1},
+		{"24.10.1-rc.1", "24.10.2-dev.1", -1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.a, tt.b), func(t *testing.T) {
+			cv, err := New("YY.0M.MICRO-MODIFIER")
+			if err != nil {
+				t.Fatal(err)
+			}
+			cv = cv.PrereleaseOrder(channels...)
+			a, err := cv.Parse(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := cv.Parse(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Compare(a, b); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrereleaseOrderSort(t *testing.T) {
+	cv, err := New("YYYY.0M.MICROMODIFIER")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cv = cv.PrereleaseOrder("dev", "alpha", "beta", "rc")
+	cvs := Calvers{}
+	for _, v := range []string{"2024.10.0-rc9", "2024.10.0-beta", "2024.10.0", "2024.10.0-rc10", "2024.10.0-dev"} {
+		ccv, err := cv.Parse(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cvs = append(cvs, ccv)
+	}
+	cvs.Sort()
+	want := []string{"2024.10.0", "2024.10.0-rc10", "2024.10.0-rc9", "2024.10.0-beta", "2024.10.0-dev"}
+	for i := range want {
+		if cvs[i].String() != want[i] {
+			t.Errorf("got %v\nwant %v", cvs[i].String(), want[i])
+		}
+	}
+	latest, err := cvs.Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.String() != "2024.10.0" {
+		t.Errorf("got %v\nwant %v", latest.String(), "2024.10.0")
+	}
+}
+
+func TestPrereleaseOrderMixed(t *testing.T) {
+	plain, err := New("YYYY.0M.MICROMODIFIER")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ordered := plain.PrereleaseOrder("dev", "alpha", "beta", "rc")
+	pep440 := plain.PEP440Order()
+	cvs := Calvers{}
+	for i, v := range []string{"2024.10.0-rc9", "2024.10.0-beta", "2024.10.0-rc10", "2024.10.0-dev", "2024.10.0-alpha", "2024.10.0"} {
+		base := plain
+		switch i % 3 {
+		case 1:
+			base = ordered
+		case 2:
+			base = pep440
+		}
+		cv, err := base.Parse(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cvs = append(cvs, cv)
+	}
+	for _, a := range cvs {
+		for _, b := range cvs {
+			if Compare(a, b) != -Compare(b, a) {
+				t.Errorf("Compare(%v, %v) = %d, but Compare(%v, %v) = %d", a, b, Compare(a, b), b, a, Compare(b, a))
+			}
+		}
+	}
+	// The ordering set on only one of the versions is used
+	a, err := plain.Parse("2024.10.0-rc10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ordered.Parse("2024.10.0-rc9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.After(b) || !b.Before(a) {
+		t.Errorf("%v should be after %v", a, b)
+	}
+}
+
+func TestNextPrereleaseWithTime(t *testing.T) {
+	tests := []struct {
+		layout  string
+		version string
+		channel string
+		want    string
+		wantErr bool
+	}{
+		{"0Y.0M.MICRO-MODIFIER", "02.02.3-rc.1", "rc", "02.02.3-rc.2", false},
+		{"0Y.0M.MICRO-MODIFIER", "02.02.3-rc.9", "rc", "02.02.3-rc.10", false},
+		{"0Y.0M.MICRO-MODIFIER", "02.02.3-rc2", "rc", "02.02.3-rc3", false},
+		{"0Y.0M.MICRO-MODIFIER", "02.02.3-rc", "rc", "02.02.3-rc.1", false},
+		{"0Y.0M.MICRO-MODIFIER", "02.02.3-beta.3", "rc", "02.02.3-rc.1", false},
+		{"0Y.0M.MICRO-MODIFIER", "02.02.3-", "rc", "02.02.4-rc.1", false},
+		{"0Y.0M.MICROMODIFIER", "02.02.3-rc.1", "-rc", "02.02.3-rc.2", false},
+		{"0Y.0M.MICROMODIFIER", "02.02.3-beta.3", "rc", "02.02.3-rc.1", false},
+		{"0Y.0M.MICROMODIFIER", "02.02.3-rc.1", "rc", "02.02.3-rc.2", false},
+		{"0Y.0M.MICROMODIFIER", "02.02.3-rc", "rc", "02.02.3-rc.1", false},
+		{"0Y.0M.MICROMODIFIER", "02.02.3", "rc", "02.02.4-rc.1", false},
+		{"0Y.0M.MICROMODIFIER", "02.02.3", "-rc", "02.02.4-rc.1", false},
+		{"0Y.0M.MICRO-MODIFIER", "02.02.3-rc.1", "", "", true},
+		{"0Y.0M.MICRO", "02.02.3", "rc", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s/%s", tt.layout, tt.version, tt.channel), func(t *testing.T) {
+			cv, err := Parse(tt.layout, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.NextPrereleaseWithTime(tt.channel, testtime)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want error")
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v\nwant %v", got.String(), tt.want)
+			}
+			if !got.PrereleaseOrder("dev", "alpha", "beta", "rc").After(cv) {
+				t.Errorf("%v is not newer than %v", got.String(), cv.String())
+			}
+		})
+	}
+}
+
+func TestRelease(t *testing.T) {
+	tests := []struct {
+		layout  string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{"0Y.0M.MICRO-MODIFIER", "02.02.3-rc.1", "02.02.3-", false},
+		{"0Y.0M.MICROMODIFIER", "02.02.3-rc.1", "02.02.3", false},
+		{"0Y.0M.MICRO", "02.02.3", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.version), func(t *testing.T) {
+			cv, err := Parse(tt.layout, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.Release()
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want error")
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v\nwant %v", got.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package calver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TokenDef is a definition of a custom token.
+type TokenDef struct {
+	// Name is the name of the token in the layout (e.g. "BUILD").
+	Name string
+	// Pattern is the regular expression that matches the value of the token. Default is "[0-9]+".
+	Pattern string
+	// MinLen is the minimum length of the value of the token. Default is 1.
+	MinLen int
+	// Render returns the value of the token from the version state.
+	// If nil, the value held by the version is rendered.
+	Render func(TokenState) string
+	// Parse returns the value held by the version from the text matched by Pattern (e.g. "5" from "build-005").
+	// It should be the inverse of Render. If nil, the matched text is held as is.
+	// If set, ordered tokens are compared by the held values instead of the rendered values.
+	Parse func(string) (string, error)
+	// Ordered is whether the token participates in ordering of versions.
+	// Ordered tokens are compared after MICRO and before MODIFIER.
+	Ordered bool
+	// Counter is whether the token is a numeric counter.
+	// Counters are reset when the time version changes and bumped up by NextWithTime when the layout has no MAJOR/MINOR/MICRO.
+	Counter bool
+}
+
+// TokenState is the state of the version passed to TokenDef.Render.
+type TokenState struct {
+	Time     time.Time
+	Major    int
+	Minor    int
+	Micro    int
+	Modifier string
+	Metadata string
+	// Value is the value of the token held by the version.
+	Value string
+}
+
+// Registry is a set of custom tokens.
+// Custom tokens are available only in layouts created by the registry.
+type Registry struct {
+	tokens []token
+}
+
+var _ token = tokenCustom{}
+
+type tokenCustom struct {
+	def    *TokenDef
+	prefix *regexp.Regexp
+	full   *regexp.Regexp
+}
+
+// NewRegistry returns *Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register registers custom token.
+func (r *Registry) Register(def TokenDef) error {
+	if def.Name == "" {
+		return errors.New("empty token name")
+	}
+	for _, t := range slices.Concat(builtinTokens, r.tokens) {
+		if t.token() == def.Name {
+			return fmt.Errorf("token '%s' is already registered", def.Name)
+		}
+	}
+	if def.Pattern == "" {
+		def.Pattern = "[0-9]+"
+	}
+	if def.MinLen == 0 {
+		def.MinLen = 1
+	}
+	prefix, err := regexp.Compile(fmt.Sprintf("^(?:%s)", def.Pattern))
+	if err != nil {
+		return fmt.Errorf("invalid pattern of token '%s': %w", def.Name, err)
+	}
+	full, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", def.Pattern))
+	if err != nil {
+		return fmt.Errorf("invalid pattern of token '%s': %w", def.Name, err)
+	}
+	r.tokens = append(r.tokens, tokenCustom{def: &def, prefix: prefix, full: full})
+	return nil
+}
+
+// New returns *Calver at the current time of the clock using builtin and registered tokens.
+func (r *Registry) New(layout string, opts ...Option) (*Calver, error) {
+	tokens, err := tokenizeLayoutWith(layout, slices.Concat(builtinTokens, r.tokens))
+	if err != nil {
+		return nil, err
+	}
+	return newCalver(tokens, opts), nil
+}
+
+// NewWithTime returns *Calver at the given time using builtin and registered tokens.
+func (r *Registry) NewWithTime(layout string, now time.Time, opts ...Option) (*Calver, error) {
+	cv, err := r.New(layout, slices.Concat([]Option{WithLocation(now.Location())}, opts)...)
+	if err != nil {
+		return nil, err
+	}
+	cv.ts = now
+	return cv, nil
+}
+
+// Parse version string using layout at the current time using builtin and registered tokens.
+func (r *Registry) Parse(layout, value string, opts ...Option) (*Calver, error) {
+	cv, err := r.New(layout, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return cv.Parse(value)
+}
+
+// Value returns the value of the custom token.
+func (cv *Calver) Value(name string) (string, error) {
+	t, err := cv.customToken(name)
+	if err != nil {
+		return "", err
+	}
+	return t.render(cv), nil
+}
+
+// WithValue returns *Calver with the value of the custom token.
+func (cv *Calver) WithValue(name, value string) (*Calver, error) {
+	t, err := cv.customToken(name)
+	if err != nil {
+		return nil, err
+	}
+	if !t.full.MatchString(value) {
+		return nil, fmt.Errorf("invalid value '%s' for token '%s'", value, name)
+	}
+	v, err := t.parseValue(value)
+	if err != nil {
+		return nil, err
+	}
+	ncv := cv.clone()
+	ncv.setValue(name, v)
+	return ncv, nil
+}
+
+// Bump returns *Calver with the custom counter token bumped up.
+func (cv *Calver) Bump(name string) (*Calver, error) {
+	t, err := cv.customToken(name)
+	if err != nil {
+		return nil, err
+	}
+	if !t.def.Counter {
+		return nil, fmt.Errorf("token '%s' is not a counter", name)
+	}
+	// Bump the value held by the version, not the rendered value
+	v := t.value(cv)
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("value '%s' of token '%s' is not numeric", v, name)
+	}
+	ncv := cv.clone()
+	ncv.setValue(name, strconv.Itoa(n+1))
+	return ncv, nil
+}
+
+func (cv *Calver) customToken(name string) (tokenCustom, error) {
+	for _, t := range cv.layout {
+		if tc, ok := t.(tokenCustom); ok && tc.token() == name {
+			return tc, nil
+		}
+	}
+	return tokenCustom{}, fmt.Errorf("no '%s' in the layout '%s'", name, cv.Layout())
+}
+
+func (cv *Calver) setValue(name, value string) {
+	if cv.values == nil {
+		cv.values = map[string]string{}
+	}
+	cv.values[name] = value
+}
+
+// resetCounters resets custom counter tokens.
+func (cv *Calver) resetCounters() {
+	for _, t := range cv.layout {
+		if tc, ok := t.(tokenCustom); ok && tc.def.Counter {
+			delete(cv.values, tc.token())
+		}
+	}
+}
+
+// lastCounter returns the name of the last custom counter token in the layout.
+func (cv *Calver) lastCounter() (string, bool) {
+	for _, t := range reverse(cv.layout) {
+		if tc, ok := t.(tokenCustom); ok && tc.def.Counter {
+			return tc.token(), true
+		}
+	}
+	return "", false
+}
+
+// compareCustom compares the values of ordered custom tokens.
+func compareCustom(a, b *Calver) int {
+	for _, t := range a.layout {
+		tc, ok := t.(tokenCustom)
+		if !ok || !tc.def.Ordered {
+			continue
+		}
+		if c := compareCustomValue(tc.orderValue(a), tc.orderValue(b)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareCustomValue compares numeric values numerically, and the others lexically.
+// Numeric values have lower precedence than non-numeric values.
+func compareCustomValue(a, b string) int {
+	return compareIdentifier(a, b, nil)
+}
+
+func (t tokenCustom) String() string {
+	return t.def.Name
+}
+
+func (t tokenCustom) token() string {
+	return t.def.Name
+}
+
+func (t tokenCustom) trimPrefix(value string) (string, string, error) {
+	return t.trimPrefixWithMaxLen(value, 0)
+}
+
+func (t tokenCustom) trimPrefixWithMaxLen(value string, maxLen int) (string, string, error) {
+	if maxLen > 0 {
+		for l := min(maxLen, len(value)); l >= t.minLen(); l-- {
+			if t.full.MatchString(value[:l]) {
+				return value[:l], value[l:], nil
+			}
+		}
+		return "", "", fmt.Errorf("could not get the value of token '%s' from '%s'", t.def.Name, value)
+	}
+	p := t.prefix.FindString(value)
+	if len(p) < t.minLen() {
+		return "", "", fmt.Errorf("could not get the value of token '%s' from '%s'", t.def.Name, value)
+	}
+	return p, strings.TrimPrefix(value, p), nil
+}
+
+func (t tokenCustom) minLen() int {
+	return t.def.MinLen
+}
+
+func (t tokenCustom) render(cv *Calver) string {
+	return t.renderValue(cv, t.value(cv))
+}
+
+// value returns the value of the token held by the version.
+func (t tokenCustom) value(cv *Calver) string {
+	v, ok := cv.values[t.def.Name]
+	if !ok && t.def.Counter {
+		v = "0"
+	}
+	return v
+}
+
+// orderValue returns the value of the token used for ordering versions.
+func (t tokenCustom) orderValue(cv *Calver) string {
+	if t.def.Parse != nil {
+		return t.value(cv)
+	}
+	return t.render(cv)
+}
+
+// parseValue returns the value of the token held by the version from the text of the token.
+func (t tokenCustom) parseValue(text string) (string, error) {
+	if t.def.Parse == nil {
+		return text, nil
+	}
+	v, err := t.def.Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid value '%s' for token '%s': %w", text, t.def.Name, err)
+	}
+	return v, nil
+}
+
+func (t tokenCustom) renderValue(cv *Calver, v string) string {
+	if t.def.Render == nil {
+		return v
+	}
+	return t.def.Render(TokenState{
+		Time:     cv.ts.In(cv.loc),
+		Major:    cv.major,
+		Minor:    cv.minor,
+		Micro:    cv.micro,
+		Modifier: cv.modifier,
+		Metadata: cv.metadata,
+		Value:    v,
+	})
+}
@@ -0,0 +1,233 @@
+package calver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		def     TokenDef
+		wantErr bool
+	}{
+		{TokenDef{Name: "BUILD", Counter: true}, false},
+		{TokenDef{Name: ""}, true},
+		{TokenDef{Name: "MICRO"}, true},
+		{TokenDef{Name: "CODENAME", Pattern: "[a-z"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.def.Name, func(t *testing.T) {
+			r := NewRegistry()
+			err := r.Register(tt.def)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want error")
+			}
+			if err := r.Register(tt.def); err == nil {
+				t.Error("want error for duplicate registration")
+			}
+		})
+	}
+}
+
+func TestRegistryCounter(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(TokenDef{Name: "BUILD", Ordered: true, Counter: true}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		version string
+		now     time.Time
+		want    string
+	}{
+		{"2024.10.15", time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC), "2024.10.16"},
+		{"2024.10.15", time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), "2024.11.0"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.version, tt.now), func(t *testing.T) {
+			cv, err := r.Parse("YYYY.0M.BUILD", tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cv.String() != tt.version {
+				t.Errorf("got %v\nwant %v", cv.String(), tt.version)
+			}
+			got, err := cv.NextWithTime(tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v\nwant %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryOrdered(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(TokenDef{Name: "BUILD", Ordered: true, Counter: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(TokenDef{Name: "CODENAME", Pattern: "[a-z]+"}); err != nil {
+		t.Fatal(err)
+	}
+	cvs := Calvers{}
+	for _, v := range []string{"24.10.9-hoge", "24.10.10-fuga", "24.09.99-piyo"} {
+		cv, err := r.Parse("YY.0M.BUILD-CODENAME", v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cvs = append(cvs, cv)
+	}
+	cvs.Sort()
+	want := []string{"24.10.10-fuga", "24.10.9-hoge", "24.09.99-piyo"}
+	for i := range want {
+		if cvs[i].String() != want[i] {
+			t.Errorf("got %v\nwant %v", cvs[i].String(), want[i])
+		}
+	}
+	a, err := cvs[0].WithValue("CODENAME", "hoge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Equal(cvs[0]) {
+		t.Errorf("%v and %v should be equal", a, cvs[0])
+	}
+	if _, err := a.WithValue("CODENAME", "HOGE"); err == nil {
+		t.Error("want error")
+	}
+	if _, err := a.Bump("CODENAME"); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestRegistryRender(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(TokenDef{
+		Name:    "FQ",
+		Pattern: "[1-4]",
+		Render: func(s TokenState) string {
+			// Fiscal year starting in April
+			return strconv.Itoa((int(s.Time.Month())+8)%12/3 + 1)
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	cv, err := r.NewWithTime("YYYY.FQ.MICRO", testtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2002.4.0"; cv.String() != want {
+		t.Errorf("got %v\nwant %v", cv.String(), want)
+	}
+	v, err := cv.Value("FQ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "4" {
+		t.Errorf("got %v\nwant %v", v, "4")
+	}
+}
+
+func TestRegistryBumpRender(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(TokenDef{
+		Name:    "BUILD",
+		Pattern: "build-[0-9]{3}",
+		Render: func(s TokenState) string {
+			n, _ := strconv.Atoi(s.Value)
+			return fmt.Sprintf("build-%03d", n)
+		},
+		Parse: func(v string) (string, error) {
+			n, err := strconv.Atoi(strings.TrimPrefix(v, "build-"))
+			if err != nil {
+				return "", err
+			}
+			return strconv.Itoa(n), nil
+		},
+		Ordered: true,
+		Counter: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	cv, err := r.NewWithTime("YYYY.0M.BUILD", testtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2002.02.build-000"; cv.String() != want {
+		t.Errorf("got %v\nwant %v", cv.String(), want)
+	}
+	for _, want := range []string{"2002.02.build-001", "2002.02.build-002"} {
+		cv, err = cv.Bump("BUILD")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cv.String() != want {
+			t.Errorf("got %v\nwant %v", cv.String(), want)
+		}
+	}
+	ncv, err := cv.NextWithTime(testtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2002.02.build-003"; ncv.String() != want {
+		t.Errorf("got %v\nwant %v", ncv.String(), want)
+	}
+	parsed, err := r.Parse("YYYY.0M.BUILD", "2002.02.build-005")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2002.02.build-005"; parsed.String() != want {
+		t.Errorf("got %v\nwant %v", parsed.String(), want)
+	}
+	bumped, err := parsed.Bump("BUILD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2002.02.build-006"; bumped.String() != want {
+		t.Errorf("got %v\nwant %v", bumped.String(), want)
+	}
+	withValue, err := parsed.WithValue("BUILD", "build-012")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cvs := Calvers{parsed, withValue, bumped}
+	cvs.Sort()
+	got := []string{}
+	for _, cv := range cvs {
+		got = append(got, cv.String())
+	}
+	if diff := cmp.Diff(got, []string{"2002.02.build-012", "2002.02.build-006", "2002.02.build-005"}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRegistryIsolation(t *testing.T) {
+	a := NewRegistry()
+	if err := a.Register(TokenDef{Name: "BUILD"}); err != nil {
+		t.Fatal(err)
+	}
+	b := NewRegistry()
+	if err := b.Register(TokenDef{Name: "BUILD", Pattern: "[a-f0-9]{7}", MinLen: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Parse("YY.0M.MICRO.BUILD", "24.10.1.abc1234"); err == nil {
+		t.Error("want error")
+	}
+	if _, err := b.Parse("YY.0M.MICRO.BUILD", "24.10.1.abc1234"); err != nil {
+		t.Error(err)
+	}
+	if _, err := Parse("YY.0M.MICRO.BUILD", "24.10.1.abc1234"); err == nil {
+		t.Error("want error")
+	}
+}
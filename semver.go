@@ -0,0 +1,204 @@
+package calver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	semverRe     = regexp.MustCompile(`^v?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+	prereleaseRe = regexp.MustCompile(`^(?:0|[1-9][0-9]*|[0-9]*[A-Za-z-][0-9A-Za-z-]*)(?:\.(?:0|[1-9][0-9]*|[0-9]*[A-Za-z-][0-9A-Za-z-]*))*$`)
+	buildRe      = regexp.MustCompile(`^[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*$`)
+)
+
+// Semver returns the semver string of the version.
+//
+// The numeric tokens of the layout (calendar tokens, MAJOR, MINOR, MICRO and custom counter tokens) are mapped to MAJOR.MINOR.PATCH of semver:
+//
+//   - With up to 3 numeric tokens, they are mapped in order and the missing fields are 0 (e.g. 'YY.0M.MICRO' 24.05.3 -> 24.5.3, 'YYYY.0M' 2024.05 -> 2024.5.0).
+//   - With more than 3 numeric tokens, adjacent tokens are packed into a field by concatenating the values zero-padded to the width of the tokens.
+//     Only fixed width tokens (e.g. 0M, MM, 0D, Q) can follow another token in a field.
+//     The first possible grouping is used, preferring shorter leading fields
+//     (e.g. 'YYYY.0M.0D.MICRO' 2024.05.07.3 -> 2024.507.3, 'YY.0M.MINOR.MICRO' 24.05.1.3 -> 2405.1.3).
+//
+// MODIFIER is mapped to the pre-release and METADATA is mapped to the build metadata.
+// Leading zeros of the fields are removed.
+func (cv *Calver) Semver() (string, error) {
+	groups, err := semverGroups(cv.layout)
+	if err != nil {
+		return "", err
+	}
+	fields := []string{"0", "0", "0"}
+	for i, g := range groups {
+		var s string
+		for j, t := range g {
+			v := semverValue(cv, t)
+			if !isNumeric(v) {
+				return "", fmt.Errorf("value '%s' of token '%s' is not numeric", v, t.token())
+			}
+			if j > 0 {
+				v = padZeros(v, semverWidth(t))
+			}
+			s += v
+		}
+		fields[i] = trimZeros(s)
+	}
+	s := strings.Join(fields, ".")
+	if cv.modifier != "" {
+		pre := trimLeadingSep(cv.modifier)
+		if !prereleaseRe.MatchString(pre) {
+			return "", fmt.Errorf("modifier '%s' is not a valid semver pre-release", cv.modifier)
+		}
+		s += "-" + pre
+	}
+	if cv.metadata != "" {
+		if !buildRe.MatchString(cv.metadata) {
+			return "", fmt.Errorf("metadata '%s' is not valid semver build metadata", cv.metadata)
+		}
+		s += "+" + cv.metadata
+	}
+	return s, nil
+}
+
+// FromSemver returns *Calver mapped from the semver string using the layout.
+// The mapping is the inverse of Semver. A leading 'v' of the semver string is allowed.
+func FromSemver(layout, s string) (*Calver, error) {
+	cv, err := New(layout)
+	if err != nil {
+		return nil, err
+	}
+	return cv.FromSemver(s)
+}
+
+// FromSemver returns *Calver mapped from the semver string using the layout of cv.
+func (cv *Calver) FromSemver(s string) (*Calver, error) {
+	m := semverRe.FindStringSubmatch(s)
+	if m == nil || (m[4] != "" && !prereleaseRe.MatchString(m[4])) || (m[5] != "" && !buildRe.MatchString(m[5])) {
+		return nil, fmt.Errorf("invalid semver '%s'", s)
+	}
+	groups, err := semverGroups(cv.layout)
+	if err != nil {
+		return nil, err
+	}
+	fields := m[1:4]
+	for _, f := range fields[len(groups):] {
+		if f != "0" {
+			return nil, fmt.Errorf("semver '%s' can not be mapped to layout '%s': the value '%s' would be lost", s, cv.Layout(), f)
+		}
+	}
+	values := map[string]string{}
+	for i, g := range groups {
+		width := 0
+		for _, t := range g[1:] {
+			width += semverWidth(t)
+		}
+		f := padZeros(fields[i], width+1)
+		values[g[0].token()] = f[:len(f)-width]
+		f = f[len(f)-width:]
+		for _, t := range g[1:] {
+			values[t.token()] = f[:semverWidth(t)]
+			f = f[semverWidth(t):]
+		}
+	}
+	if m[4] != "" && !contains(cv.layout, tMODIFIER) {
+		return nil, fmt.Errorf("no 'MODIFIER' in the layout '%s'", cv.Layout())
+	}
+	if m[5] != "" && !contains(cv.layout, tMETADATA) {
+		return nil, fmt.Errorf("no 'METADATA' in the layout '%s'", cv.Layout())
+	}
+	ncv, err := cv.parseValues(values, m[4], m[5])
+	if err != nil {
+		return nil, fmt.Errorf("semver '%s' can not be mapped to layout '%s': %w", s, cv.Layout(), err)
+	}
+	return ncv, nil
+}
+
+// semverGroups returns the numeric tokens of the layout grouped into the fields of semver.
+func semverGroups(layout []token) ([][]token, error) {
+	nums, err := numericTokens(layout)
+	if err != nil {
+		return nil, err
+	}
+	switch n := len(nums); {
+	case n <= 3:
+		groups := [][]token{}
+		for _, t := range nums {
+			groups = append(groups, []token{t})
+		}
+		return groups, nil
+	default:
+		for a := 1; a <= n-2; a++ {
+			for b := 1; a+b <= n-1; b++ {
+				groups := [][]token{nums[:a], nums[a : a+b], nums[a+b:]}
+				if packable(groups) {
+					return groups, nil
+				}
+			}
+		}
+		return nil, errors.New("the layout can not be mapped to semver")
+	}
+}
+
+// numericTokens returns the numeric tokens of the layout (calendar tokens, MAJOR, MINOR, MICRO and custom counter tokens).
+func numericTokens(layout []token) ([]token, error) {
+	nums := []token{}
+	for _, t := range layout {
+		switch tt := t.(type) {
+		case tokenCal:
+			nums = append(nums, t)
+		case tokenVer:
+			if !tt.isText() {
+				nums = append(nums, t)
+			}
+		case tokenCustom:
+			if !tt.def.Counter {
+				return nil, fmt.Errorf("token '%s' is not numeric", t.token())
+			}
+			nums = append(nums, t)
+		}
+	}
+	if len(nums) == 0 {
+		return nil, errors.New("no numeric token in the layout")
+	}
+	return nums, nil
+}
+
+// packable returns true if all the tokens following the first token of each group have fixed width.
+func packable(groups [][]token) bool {
+	for _, g := range groups {
+		for _, t := range g[1:] {
+			if semverWidth(t) == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// semverWidth returns the width of the token packed into a semver field. 0 means variable width.
+func semverWidth(t token) int {
+	tc, ok := t.(tokenCal)
+	switch {
+	case !ok:
+		return 0
+	case tc.digits > 0:
+		return tc.digits
+	case tc.maxDigits <= 3:
+		return tc.maxDigits
+	}
+	return 0
+}
+
+func semverValue(cv *Calver, t token) string {
+	switch tt := t.(type) {
+	case tokenCal:
+		return tt.timeToString(cv.ts.In(cv.loc))
+	case tokenVer:
+		return tt.verToString(cv)
+	case tokenCustom:
+		return tt.value(cv)
+	}
+	return ""
+}
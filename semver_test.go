@@ -0,0 +1,115 @@
+package calver
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSemver(t *testing.T) {
+	tests := []struct {
+		layout  string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{"YY.0M.MICRO", "24.05.3", "24.5.3", false},
+		{"YYYY.0M", "2024.05", "2024.5.0", false},
+		{"YYYY.MM.DD", "2024.5.7", "2024.5.7", false},
+		{"MAJOR.MINOR.MICRO", "1.2.3", "1.2.3", false},
+		{"YYYY.0M.0D.MICRO", "2024.05.07.3", "2024.507.3", false},
+		{"YYYY.0M.0D.MICRO", "2024.10.17.0", "2024.1017.0", false},
+		{"YY.0M.MINOR.MICRO", "24.05.1.3", "2405.1.3", false},
+		{"YYYY.0M.0D.0H0MI", "2024.01.02.0304", "2024.1.20304", false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.3-rc.1", "24.5.3-rc.1", false},
+		{"YY.0M.MICRO-MODIFIER+METADATA", "24.05.3-rc.1+build.5", "24.5.3-rc.1+build.5", false},
+		{"YY.0M.MICROMODIFIER", "24.05.3-beta", "24.5.3-beta", false},
+		{"YY.0M[.MICRO]", "24.05", "24.5.0", false},
+		{"YY.0M.MICRO-MODIFIER", "24.05.3-rc_1", "", true},
+		{"YY.0M.MICRO-MODIFIER", "24.05.3-rc.01", "", true},
+		{"YY.MINOR.MICRO.MAJOR", "24.1.2.3", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.version), func(t *testing.T) {
+			cv, err := Parse(tt.layout, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cv.Semver()
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+			back, err := FromSemver(tt.layout, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back.String() != cv.String() {
+				t.Errorf("got %v\nwant %v", back.String(), cv.String())
+			}
+			if !back.Equal(cv) {
+				t.Errorf("%v and %v should be equal", back, cv)
+			}
+		})
+	}
+}
+
+func TestFromSemver(t *testing.T) {
+	tests := []struct {
+		layout  string
+		semver  string
+		want    string
+		wantErr bool
+	}{
+		{"YY.0M.MICRO", "24.5.3", "24.05.3", false},
+		{"YY.0M.MICRO", "v24.5.3", "24.05.3", false},
+		{"YYYY.0M", "2024.5.0", "2024.05", false},
+		{"YYYY.0M", "2024.5.1", "", true},
+		{"YYYY.0M.0D.MICRO", "2024.1017.2", "2024.10.17.2", false},
+		{"YYYY.0M.0D.MICRO", "2024.1317.2", "", true},
+		{"YY.0M.MICRO", "24.5.3-rc.1", "", true},
+		{"YY.0M.MICRO-MODIFIER", "24.5.3-rc.1", "24.05.3-rc.1", false},
+		{"YY.0M.MICRO", "24.5", "", true},
+		{"YY.0M.MICRO", "24.05.3", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.semver), func(t *testing.T) {
+			got, err := FromSemver(tt.layout, tt.semver)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v\nwant %v", got.String(), tt.want)
+			}
+			s, err := got.Semver()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != trimLeadingV(tt.semver) {
+				t.Errorf("got %v\nwant %v", s, tt.semver)
+			}
+		})
+	}
+}
+
+func trimLeadingV(s string) string {
+	if len(s) > 0 && s[0] == 'v' {
+		return s[1:]
+	}
+	return s
+}
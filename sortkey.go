@@ -0,0 +1,187 @@
+package calver
+
+import (
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sortKeySep separates the ordered part and the values of the sort key. It is not used in the ordered part.
+const sortKeySep = "~"
+
+// keyIntLen is the length of an integer in the sort key.
+const keyIntLen = 16
+
+// keyInts is the number of the integers at the start of the sort key.
+const keyInts = 5
+
+// SortKey returns the key of the version whose byte ordering is consistent with Compare (and Calvers.Sort).
+//
+// The key starts with the timestamp (Unix seconds and nanoseconds), MAJOR, MINOR and MICRO encoded as fixed-width hexadecimal,
+// followed by the ordered custom tokens and MODIFIER encoded according to the ordering of cv (e.g. PrereleaseOrder, PEP440Order).
+// The rest of the key after '~' holds the values to restore the version by FromSortKey.
+// The values only affect the ordering of versions that Compare reports as equal (e.g. 'rc.1' and 'rc.01' with PrereleaseOrder).
+func (cv *Calver) SortKey() string {
+	var b strings.Builder
+	b.WriteString(intKey(cv.ts.Unix()))
+	b.WriteString(intKey(int64(cv.ts.Nanosecond())))
+	b.WriteString(intKey(int64(cv.major)))
+	b.WriteString(intKey(int64(cv.minor)))
+	b.WriteString(intKey(int64(cv.micro)))
+	for _, t := range cv.layout {
+		if tc, ok := t.(tokenCustom); ok && tc.def.Ordered {
+			b.WriteString(identifierKey(tc.orderValue(cv), nil))
+		}
+	}
+	modifierKey := cv.modifierKey
+	if modifierKey == nil {
+		modifierKey = defaultModifierKey
+	}
+	b.WriteString(modifierKey(cv.modifier))
+	b.WriteString(sortKeySep)
+	b.WriteString(textKey(cv.modifier))
+	b.WriteString(textKey(cv.metadata))
+	for _, t := range cv.layout {
+		if tc, ok := t.(tokenCustom); ok {
+			b.WriteString(textKey(cv.values[tc.token()]))
+		}
+	}
+	return b.String()
+}
+
+// FromSortKey returns *Calver restored from the sort key using the layout.
+// The restoring is the inverse of SortKey.
+func FromSortKey(layout, key string) (*Calver, error) {
+	cv, err := New(layout)
+	if err != nil {
+		return nil, err
+	}
+	return cv.FromSortKey(key)
+}
+
+// FromSortKey returns *Calver restored from the sort key using the layout and the ordering of cv.
+func (cv *Calver) FromSortKey(key string) (*Calver, error) {
+	_, rest, ok := strings.Cut(key, sortKeySep)
+	if !ok || len(key) < keyIntLen*keyInts {
+		return nil, fmt.Errorf("invalid sort key '%s'", key)
+	}
+	ints := make([]int64, keyInts)
+	for i := range ints {
+		n, err := parseIntKey(key[i*keyIntLen : (i+1)*keyIntLen])
+		if err != nil {
+			return nil, fmt.Errorf("invalid sort key '%s': %w", key, err)
+		}
+		ints[i] = n
+	}
+	texts := strings.Split(rest, ".")
+	customs := []tokenCustom{}
+	for _, t := range cv.layout {
+		if tc, ok := t.(tokenCustom); ok {
+			customs = append(customs, tc)
+		}
+	}
+	if len(texts) != len(customs)+3 || texts[len(texts)-1] != "" {
+		return nil, fmt.Errorf("invalid sort key '%s' for the layout '%s'", key, cv.Layout())
+	}
+	values := make([]string, len(texts)-1)
+	for i, t := range texts[:len(texts)-1] {
+		v, err := hex.DecodeString(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sort key '%s': %w", key, err)
+		}
+		values[i] = string(v)
+	}
+	ncv := cv.clone()
+	ncv.ts = time.Unix(ints[0], ints[1]).UTC()
+	ncv.major = int(ints[2])
+	ncv.minor = int(ints[3])
+	ncv.micro = int(ints[4])
+	ncv.modifier = values[0]
+	ncv.metadata = values[1]
+	ncv.values = nil
+	for i, tc := range customs {
+		if values[i+2] != "" {
+			ncv.setValue(tc.token(), values[i+2])
+		}
+	}
+	if ncv.SortKey() != key {
+		return nil, fmt.Errorf("invalid sort key '%s' for the layout '%s'", key, cv.Layout())
+	}
+	return ncv, nil
+}
+
+// intKey returns the fixed-width key of the integer. Negative integers sort before positive integers.
+func intKey(n int64) string {
+	return fmt.Sprintf("%016x", uint64(n)^(1<<63))
+}
+
+func parseIntKey(s string) (int64, error) {
+	u, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(u ^ (1 << 63)), nil
+}
+
+// textKey returns the key of the text that sorts in byte order. '.' terminates the text.
+func textKey(s string) string {
+	return hex.EncodeToString([]byte(s)) + "."
+}
+
+// identifierKey returns the key of the identifier consistent with compareIdentifier.
+// Numeric identifiers ('1') sort before identifiers not in channels ('2'), and identifiers in channels ('3').
+func identifierKey(id string, channels []string) string {
+	if isNumeric(id) {
+		id = strings.TrimLeft(id, "0")
+		return fmt.Sprintf("1%04x%s", len(id), id)
+	}
+	for i, ch := range channels {
+		if ch == id {
+			return fmt.Sprintf("3%04x", i)
+		}
+	}
+	return "2" + textKey(id)
+}
+
+// defaultModifierKey returns the key of the modifier consistent with the lexical ordering of modifiers.
+func defaultModifierKey(m string) string {
+	if m == "" {
+		return "1"
+	}
+	return "0" + textKey(m)
+}
+
+// prereleaseModifierKey returns the key of the modifier consistent with prereleaseComparator.
+func prereleaseModifierKey(channels []string) func(m string) string {
+	channels = slices.Clone(channels)
+	return func(m string) string {
+		if m == "" {
+			return "1"
+		}
+		var b strings.Builder
+		b.WriteString("0")
+		for _, id := range splitIdentifiers(m) {
+			b.WriteString(identifierKey(id, channels))
+		}
+		// End of the identifiers sorts before any identifier
+		b.WriteString("0")
+		return b.String()
+	}
+}
+
+// pep440ModifierKey returns the key of the modifier consistent with comparePEP440Modifier.
+func pep440ModifierKey(m string) string {
+	s := parsePEP440Modifier(m)
+	if s.invalid {
+		return "0" + textKey(m)
+	}
+	var b strings.Builder
+	b.WriteString("1")
+	for _, n := range s.key() {
+		b.WriteString(intKey(int64(n)))
+	}
+	return b.String()
+}
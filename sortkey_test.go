@@ -0,0 +1,180 @@
+package calver
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSortKey(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(TokenDef{Name: "BUILD", Ordered: true, Counter: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(TokenDef{Name: "CODENAME", Pattern: "[a-z]+"}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		layout   string
+		order    func(cv *Calver) *Calver
+		versions []string
+	}{
+		{
+			"YY.MM.MICRO",
+			nil,
+			[]string{"24.9.0", "24.10.0", "24.9.10", "24.9.9", "23.12.100", "25.1.0", "1.1.0"},
+		},
+		{
+			"YY.MM.MICRO[-MODIFIER][+METADATA]",
+			nil,
+			[]string{"24.9.0", "24.9.0-rc", "24.9.0-rc1", "24.9.0-beta", "24.9.0-r", "24.9.0+build.1", "24.9.0-rc+build.1", "24.10.0-alpha"},
+		},
+		{
+			"YY.MM.MICRO[-MODIFIER]",
+			func(cv *Calver) *Calver { return cv.PrereleaseOrder() },
+			[]string{"24.9.0", "24.9.0-rc.1", "24.9.0-rc.2", "24.9.0-rc.10", "24.9.0-rc.01", "24.9.0-rc", "24.9.0-rc.1.1", "24.9.0-1", "24.9.0-beta.2", "24.9.0-rc.a"},
+		},
+		{
+			"YY.MM.MICRO[-MODIFIER]",
+			func(cv *Calver) *Calver { return cv.PrereleaseOrder("dev", "alpha", "beta", "rc") },
+			[]string{"24.9.0", "24.9.0-rc.1", "24.9.0-rc.10", "24.9.0-dev.1", "24.9.0-alpha", "24.9.0-beta.2", "24.9.0-hotfix.1", "24.9.0-1", "24.10.0-dev.1"},
+		},
+		{
+			"YY.MM.MICRO[-MODIFIER]",
+			func(cv *Calver) *Calver { return cv.PEP440Order() },
+			[]string{"24.9.0", "24.9.0-dev.1", "24.9.0-alpha.1", "24.9.0-a.1", "24.9.0-rc.1.dev.1", "24.9.0-rc.1", "24.9.0-post.1", "24.9.0-post.1.dev.2", "24.9.0-hotfix", "24.9.0-foo"},
+		},
+		{
+			"YY.0M.BUILD-CODENAME",
+			nil,
+			[]string{"24.10.9-hoge", "24.10.10-fuga", "24.09.99-piyo", "24.10.10-hoge"},
+		},
+		{
+			"MAJOR.MINOR.MICRO",
+			nil,
+			[]string{"1.2.3", "1.10.0", "0.0.1", "10.0.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.layout, tt.versions), func(t *testing.T) {
+			base, err := r.New(tt.layout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.order != nil {
+				base = tt.order(base)
+			}
+			cvs := Calvers{}
+			for _, v := range tt.versions {
+				cv, err := base.Parse(v)
+				if err != nil {
+					t.Fatal(err)
+				}
+				cvs = append(cvs, cv)
+			}
+			for _, a := range cvs {
+				for _, b := range cvs {
+					c := Compare(a, b)
+					if c == 0 {
+						continue
+					}
+					if got := strings.Compare(a.SortKey(), b.SortKey()); got != c {
+						t.Errorf("Compare(%v, %v) = %d, but the sort keys are compared as %d", a, b, c, got)
+					}
+				}
+				key := a.SortKey()
+				back, err := base.FromSortKey(key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if back.String() != a.String() {
+					t.Errorf("got %v\nwant %v", back.String(), a.String())
+				}
+				if back.SortKey() != key {
+					t.Errorf("got %v\nwant %v", back.SortKey(), key)
+				}
+			}
+		})
+	}
+}
+
+func TestSortKeyFixedWidth(t *testing.T) {
+	a, err := Parse("YY.MM.MICRO", "24.9.9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Parse("YY.MM.MICRO", "24.10.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.SortKey()) != len(b.SortKey()) {
+		t.Errorf("got %v and %v\nwant the same length", a.SortKey(), b.SortKey())
+	}
+	if a.SortKey() >= b.SortKey() {
+		t.Errorf("%v should be less than %v", a.SortKey(), b.SortKey())
+	}
+}
+
+func TestFromSortKey(t *testing.T) {
+	cv, err := Parse("YY.0M.MICRO-MODIFIER", "24.05.1-rc.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := cv.SortKey()
+	tests := []struct {
+		layout  string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{"YY.0M.MICRO-MODIFIER", key, "24.05.1-rc.1", false},
+		{"YYYY.0M.MICRO-MODIFIER", key, "2024.05.1-rc.1", false},
+		{"YY.0M.MICRO-MODIFIER", strings.Replace(key, "~", "", 1), "", true},
+		{"YY.0M.MICRO-MODIFIER", key[:10], "", true},
+		{"YY.0M.MICRO-MODIFIER", "z" + key[1:], "", true},
+		{"YY.0M.MICRO-MODIFIER", key + "00.", "", true},
+		{"YY.0M.MICRO-MODIFIER", strings.Replace(key, "~7263", "~7265", 1), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.layout, tt.key), func(t *testing.T) {
+			got, err := FromSortKey(tt.layout, tt.key)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Errorf("want error, got %v", got)
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %v\nwant %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSortKeyYears(t *testing.T) {
+	// Ascending order
+	versions := []string{"0001.01", "1677.12", "2200.01", "2262.05", "2300.01", "9999.12"}
+	prev := ""
+	for _, v := range versions {
+		cv, err := Parse("YYYY.0M", v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key := cv.SortKey()
+		if key <= prev {
+			t.Errorf("%v should be greater than %v", key, prev)
+		}
+		prev = key
+		back, err := FromSortKey("YYYY.0M", key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back.String() != v {
+			t.Errorf("got %v\nwant %v", back.String(), v)
+		}
+	}
+}
@@ -23,6 +23,9 @@ var (
 
 type tokenCal struct {
 	t            string
+	digits       int  // number of digits of fixed length token. 0 means variable length
+	maxDigits    int  // maximum number of digits of variable length token
+	zero         bool // whether variable length token can be zero (e.g. HH)
 	timeToString func(time.Time) string
 }
 
@@ -39,17 +42,20 @@ func (t tokenCal) trimPrefix(value string) (string, string, error) {
 }
 
 func (t tokenCal) trimPrefixWithMaxLen(value string, maxLen int) (string, string, error) {
-	l := len(t.t)
 	var expr string
-	if l == 2 && !strings.HasPrefix(t.t, "0") {
-		// Variable length token (MM, DD, WW, YY)
-		if maxLen > 0 && maxLen < 2 {
-			expr = "^([1-9])(.*)$"
+	if t.digits == 0 {
+		// Variable length token (YY, MM, WW, DD, DDD, HH, MI, EPOCH)
+		maxDigits := t.maxDigits
+		if maxLen > 0 && maxLen < maxDigits {
+			maxDigits = maxLen
+		}
+		if t.zero {
+			expr = fmt.Sprintf("^(0|[1-9][0-9]{0,%d})(.*)$", maxDigits-1)
 		} else {
-			expr = "^([1-9][0-9]?)(.*)$"
+			expr = fmt.Sprintf("^([1-9][0-9]{0,%d})(.*)$", maxDigits-1)
 		}
 	} else {
-		expr = fmt.Sprintf("^([0-9]{%d})(.*)$", l)
+		expr = fmt.Sprintf("^([0-9]{%d})(.*)$", t.digits)
 	}
 	re := regexp.MustCompile(expr)
 	matches := re.FindAllStringSubmatch(value, -1)
@@ -60,18 +66,17 @@ func (t tokenCal) trimPrefixWithMaxLen(value string, maxLen int) (string, string
 }
 
 func (t tokenCal) minLen() int {
-	l := len(t.t)
-	// Variable length tokens (MM, DD, WW, YY) have minimum length of 1
-	if l == 2 && !strings.HasPrefix(t.t, "0") {
+	// Variable length tokens (YY, MM, WW, DD, DDD, HH, MI, EPOCH) have minimum length of 1
+	if t.digits == 0 {
 		return 1
 	}
-	// Fixed length tokens (YYYY, 0M, 0D, 0W, 0Y)
-	return l
+	// Fixed length tokens (YYYY, 0Y, Q, 0Q, 0M, 0W, 0D, 0DDD, 0H, 0MI)
+	return t.digits
 }
 
 type tokenVer struct {
 	t           string
-	verToString func(int, int, int, string) string
+	verToString func(*Calver) string
 }
 
 func (t tokenVer) String() string {
@@ -87,7 +92,7 @@ func (t tokenVer) trimPrefix(value string) (string, string, error) {
 }
 
 func (t tokenVer) trimPrefixWithMaxLen(value string, maxLen int) (string, string, error) {
-	if t.t == "MODIFIER" {
+	if t.isText() {
 		return value, "", nil
 	}
 	var expr string
@@ -105,25 +110,52 @@ func (t tokenVer) trimPrefixWithMaxLen(value string, maxLen int) (string, string
 }
 
 func (t tokenVer) minLen() int {
-	if t.t == "MODIFIER" {
+	if t.isText() {
 		return 0
 	}
 	return 1
 }
 
+// isText returns true if the token is a free text token (MODIFIER, METADATA).
+func (t tokenVer) isText() bool {
+	return t.t == tMODIFIER.t || t.t == tMETADATA.t
+}
+
+// isTextToken returns true if the token is a free text token (MODIFIER, METADATA).
+func isTextToken(t token) bool {
+	tv, ok := t.(tokenVer)
+	return ok && tv.isText()
+}
+
+// isCustomToken returns true if the token is a custom token registered to Registry.
+func isCustomToken(t token) bool {
+	_, ok := t.(tokenCustom)
+	return ok
+}
+
 type tokenSep struct {
 	t string
+	// raw is the quoted or escaped form of the separator in the layout (e.g. 'ADD', \M). If empty, t is used.
+	raw string
 }
 
 func newTokenSep(token string) tokenSep {
 	return tokenSep{t: token}
 }
 
+// newTokenLiteral returns the separator of the literal text quoted or escaped in the layout.
+func newTokenLiteral(text, raw string) tokenSep {
+	return tokenSep{t: text, raw: raw}
+}
+
 func (t tokenSep) String() string {
 	return t.t
 }
 
 func (t tokenSep) token() string {
+	if t.raw != "" {
+		return t.raw
+	}
 	return t.t
 }
 
@@ -143,56 +175,150 @@ func (t tokenSep) minLen() int {
 }
 
 var (
-	tYYYY = tokenCal{t: "YYYY", timeToString: func(t time.Time) string { return t.Format("2006") }}
-	tYY   = tokenCal{t: "YY", timeToString: func(t time.Time) string { return strings.TrimPrefix(t.Format("06"), "0") }}
-	t0Y   = tokenCal{t: "0Y", timeToString: func(t time.Time) string { return t.Format("06") }}
-	tMM   = tokenCal{t: "MM", timeToString: func(t time.Time) string { return t.Format("1") }}
-	t0M   = tokenCal{t: "0M", timeToString: func(t time.Time) string { return t.Format("01") }}
-	tWW   = tokenCal{t: "WW", timeToString: func(t time.Time) string {
+	tYYYY = tokenCal{t: "YYYY", digits: 4, timeToString: func(t time.Time) string { return t.Format("2006") }}
+	tYY   = tokenCal{t: "YY", maxDigits: 2, timeToString: func(t time.Time) string { return strings.TrimPrefix(t.Format("06"), "0") }}
+	t0Y   = tokenCal{t: "0Y", digits: 2, timeToString: func(t time.Time) string { return t.Format("06") }}
+	tGGGG = tokenCal{t: "GGGG", digits: 4, timeToString: func(t time.Time) string {
+		y, _ := t.ISOWeek()
+		return fmt.Sprintf("%04d", y)
+	}}
+	tGG = tokenCal{t: "GG", maxDigits: 2, timeToString: func(t time.Time) string {
+		y, _ := t.ISOWeek()
+		return strings.TrimPrefix(fmt.Sprintf("%02d", y%100), "0")
+	}}
+	t0G = tokenCal{t: "0G", digits: 2, timeToString: func(t time.Time) string {
+		y, _ := t.ISOWeek()
+		return fmt.Sprintf("%02d", y%100)
+	}}
+	tQ  = tokenCal{t: "Q", digits: 1, timeToString: func(t time.Time) string { return fmt.Sprintf("%d", quarter(t)) }}
+	t0Q = tokenCal{t: "0Q", digits: 2, timeToString: func(t time.Time) string { return fmt.Sprintf("%02d", quarter(t)) }}
+	tMM = tokenCal{t: "MM", maxDigits: 2, timeToString: func(t time.Time) string { return t.Format("1") }}
+	t0M = tokenCal{t: "0M", digits: 2, timeToString: func(t time.Time) string { return t.Format("01") }}
+	tWW = tokenCal{t: "WW", maxDigits: 2, timeToString: func(t time.Time) string {
 		_, w := t.ISOWeek()
 		return fmt.Sprintf("%d", w)
 	}}
-	t0W = tokenCal{t: "0W", timeToString: func(t time.Time) string {
+	t0W = tokenCal{t: "0W", digits: 2, timeToString: func(t time.Time) string {
 		_, w := t.ISOWeek()
 		return fmt.Sprintf("%02d", w)
 	}}
-	tDD = tokenCal{t: "DD", timeToString: func(t time.Time) string { return t.Format("2") }}
-	t0D = tokenCal{t: "0D", timeToString: func(t time.Time) string { return t.Format("02") }}
+	tDD    = tokenCal{t: "DD", maxDigits: 2, timeToString: func(t time.Time) string { return t.Format("2") }}
+	t0D    = tokenCal{t: "0D", digits: 2, timeToString: func(t time.Time) string { return t.Format("02") }}
+	tDDD   = tokenCal{t: "DDD", maxDigits: 3, timeToString: func(t time.Time) string { return fmt.Sprintf("%d", t.YearDay()) }}
+	t0DDD  = tokenCal{t: "0DDD", digits: 3, timeToString: func(t time.Time) string { return fmt.Sprintf("%03d", t.YearDay()) }}
+	tHH    = tokenCal{t: "HH", maxDigits: 2, zero: true, timeToString: func(t time.Time) string { return fmt.Sprintf("%d", t.Hour()) }}
+	t0H    = tokenCal{t: "0H", digits: 2, timeToString: func(t time.Time) string { return fmt.Sprintf("%02d", t.Hour()) }}
+	tMI    = tokenCal{t: "MI", maxDigits: 2, zero: true, timeToString: func(t time.Time) string { return fmt.Sprintf("%d", t.Minute()) }}
+	t0MI   = tokenCal{t: "0MI", digits: 2, timeToString: func(t time.Time) string { return fmt.Sprintf("%02d", t.Minute()) }}
+	tEPOCH = tokenCal{t: "EPOCH", maxDigits: 19, zero: true, timeToString: func(t time.Time) string { return fmt.Sprintf("%d", t.Unix()) }}
 
-	tMAJOR    = tokenVer{t: "MAJOR", verToString: func(major, minor, micro int, modifier string) string { return fmt.Sprintf("%d", major) }}
-	tMINOR    = tokenVer{t: "MINOR", verToString: func(major, minor, micro int, modifier string) string { return fmt.Sprintf("%d", minor) }}
-	tMICRO    = tokenVer{t: "MICRO", verToString: func(major, minor, micro int, modifier string) string { return fmt.Sprintf("%d", micro) }}
-	tMODIFIER = tokenVer{t: "MODIFIER", verToString: func(major, minor, micro int, modifier string) string { return modifier }}
+	tMAJOR    = tokenVer{t: "MAJOR", verToString: func(cv *Calver) string { return fmt.Sprintf("%d", cv.major) }}
+	tMINOR    = tokenVer{t: "MINOR", verToString: func(cv *Calver) string { return fmt.Sprintf("%d", cv.minor) }}
+	tMICRO    = tokenVer{t: "MICRO", verToString: func(cv *Calver) string { return fmt.Sprintf("%d", cv.micro) }}
+	tMODIFIER = tokenVer{t: "MODIFIER", verToString: func(cv *Calver) string { return cv.modifier }}
+	tMETADATA = tokenVer{t: "METADATA", verToString: func(cv *Calver) string { return cv.metadata }}
 )
 
 var builtinTokens = []token{
 	tYYYY,
 	tYY,
 	t0Y,
+	tGGGG,
+	tGG,
+	t0G,
+	tQ,
+	t0Q,
 	tMM,
 	t0M,
 	tWW,
 	t0W,
 	tDD,
 	t0D,
+	tDDD,
+	t0DDD,
+	tHH,
+	t0H,
+	tMI,
+	t0MI,
+	tEPOCH,
 	tMAJOR,
 	tMINOR,
 	tMICRO,
 	tMODIFIER,
+	tMETADATA,
 }
 
 func tokenizeLayout(layout string) ([]token, error) {
+	return tokenizeLayoutWith(layout, builtinTokens)
+}
+
+func tokenizeLayoutWith(layout string, available []token) ([]token, error) {
 	tokens := []token{}
 	splitted := strings.Split(layout, "")
 	size := len(splitted)
 	pos := 0
 	for idx := 0; idx < size; idx++ {
+		switch splitted[idx] {
+		case "'":
+			// Quoted literal text ('' is a single quote)
+			if pos < idx {
+				tokens = append(tokens, matchToken(strings.Join(splitted[pos:idx], ""), available))
+			}
+			end := idx + 1
+			var text strings.Builder
+			for {
+				if end >= size {
+					return nil, fmt.Errorf("unterminated quote in the layout '%s'", layout)
+				}
+				if splitted[end] == "'" {
+					if end+1 < size && splitted[end+1] == "'" {
+						text.WriteString("'")
+						end += 2
+						continue
+					}
+					break
+				}
+				text.WriteString(splitted[end])
+				end++
+			}
+			if end == idx+1 {
+				text.WriteString("'")
+			}
+			tokens = append(tokens, newTokenLiteral(text.String(), strings.Join(splitted[idx:end+1], "")))
+			idx = end
+			pos = idx + 1
+			continue
+		case "\\":
+			// Escaped character
+			if pos < idx {
+				tokens = append(tokens, matchToken(strings.Join(splitted[pos:idx], ""), available))
+			}
+			if idx+1 >= size {
+				return nil, fmt.Errorf("trailing backslash in the layout '%s'", layout)
+			}
+			tokens = append(tokens, newTokenLiteral(splitted[idx+1], strings.Join(splitted[idx:idx+2], "")))
+			idx++
+			pos = idx + 1
+			continue
+		}
+		if splitted[idx] == "[" || splitted[idx] == "]" {
+			if pos < idx {
+				tokens = append(tokens, matchToken(strings.Join(splitted[pos:idx], ""), available))
+			}
+			if splitted[idx] == "[" {
+				tokens = append(tokens, tOptionalOpen)
+			} else {
+				tokens = append(tokens, tOptionalClose)
+			}
+			pos = idx + 1
+			continue
+		}
 		v := strings.Join(splitted[pos:idx+1], "")
 		prev := strings.Join(splitted[pos:idx], "")
 		var match token
 		var prevMatch token
 		prefixMatches := []token{}
-		for _, t := range builtinTokens {
+		for _, t := range available {
 			if strings.HasPrefix(t.token(), v) {
 				prefixMatches = append(prefixMatches, t)
 			}
@@ -224,9 +350,28 @@ func tokenizeLayout(layout string) ([]token, error) {
 			tokens = append(tokens, newTokenSep(v))
 		}
 	}
+	if err := checkOptional(tokens); err != nil {
+		return nil, err
+	}
 	if !lessThanOneContains(tokens, []token{tYYYY, tYY, t0Y}) {
 		return nil, fmt.Errorf("only one of %v, %v, %v can be included in the layout", tYYYY, tYY, t0Y)
 	}
+	if !lessThanOneContains(tokens, []token{tGGGG, tGG, t0G}) {
+		return nil, fmt.Errorf("only one of %v, %v, %v can be included in the layout", tGGGG, tGG, t0G)
+	}
+	// The ISO week-year only determines the date with the ISO week
+	if containsAny(tokens, []token{tGGGG, tGG, t0G}) && !containsAny(tokens, []token{tWW, t0W}) {
+		return nil, fmt.Errorf("%v, %v, %v can not be included in the layout without %v, %v", tGGGG, tGG, t0G, tWW, t0W)
+	}
+	if containsAny(tokens, []token{tGGGG, tGG, t0G}) && containsAny(tokens, []token{tYYYY, tYY, t0Y, tQ, t0Q, tMM, t0M, tDD, t0D, tDDD, t0DDD}) {
+		return nil, fmt.Errorf("%v, %v, %v can not be included in the layout with %v, %v, %v, %v, %v, %v, %v, %v, %v, %v, %v", tGGGG, tGG, t0G, tYYYY, tYY, t0Y, tQ, t0Q, tMM, t0M, tDD, t0D, tDDD, t0DDD)
+	}
+	if !lessThanOneContains(tokens, []token{tQ, t0Q}) {
+		return nil, fmt.Errorf("only one of %v, %v can be included in the layout", tQ, t0Q)
+	}
+	if containsAny(tokens, []token{tQ, t0Q}) && containsAny(tokens, []token{tMM, t0M, tWW, t0W, tDD, t0D}) {
+		return nil, fmt.Errorf("%v, %v can not be included in the layout with %v, %v, %v, %v, %v, %v", tQ, t0Q, tMM, t0M, tWW, t0W, tDD, t0D)
+	}
 	if !lessThanOneContains(tokens, []token{tMM, t0M}) {
 		return nil, fmt.Errorf("only one of %v, %v can be included in the layout", tMM, t0M)
 	}
@@ -236,6 +381,21 @@ func tokenizeLayout(layout string) ([]token, error) {
 	if !lessThanOneContains(tokens, []token{tDD, t0D}) {
 		return nil, fmt.Errorf("only one of %v, %v can be included in the layout", tDD, t0D)
 	}
+	if !lessThanOneContains(tokens, []token{tDDD, t0DDD}) {
+		return nil, fmt.Errorf("only one of %v, %v can be included in the layout", tDDD, t0DDD)
+	}
+	if containsAny(tokens, []token{tDDD, t0DDD}) && containsAny(tokens, []token{tQ, t0Q, tMM, t0M, tWW, t0W, tDD, t0D}) {
+		return nil, fmt.Errorf("%v, %v can not be included in the layout with %v, %v, %v, %v, %v, %v, %v, %v", tDDD, t0DDD, tQ, t0Q, tMM, t0M, tWW, t0W, tDD, t0D)
+	}
+	if !lessThanOneContains(tokens, []token{tHH, t0H}) {
+		return nil, fmt.Errorf("only one of %v, %v can be included in the layout", tHH, t0H)
+	}
+	if !lessThanOneContains(tokens, []token{tMI, t0MI}) {
+		return nil, fmt.Errorf("only one of %v, %v can be included in the layout", tMI, t0MI)
+	}
+	if !lessThanOneContains(tokens, []token{tEPOCH}) {
+		return nil, fmt.Errorf("only one %v can be included in the layout", tEPOCH)
+	}
 	if !lessThanOneContains(tokens, []token{tMAJOR}) {
 		return nil, fmt.Errorf("only one %v can be included in the layout", tMAJOR)
 	}
@@ -248,10 +408,62 @@ func tokenizeLayout(layout string) ([]token, error) {
 	if !lessThanOneContains(tokens, []token{tMODIFIER}) {
 		return nil, fmt.Errorf("only one %v can be included in the layout", tMODIFIER)
 	}
+	if !lessThanOneContains(tokens, []token{tMETADATA}) {
+		return nil, fmt.Errorf("only one %v can be included in the layout", tMETADATA)
+	}
+	for _, t := range tokens {
+		if isCustomToken(t) && !lessThanOneContains(tokens, []token{t}) {
+			return nil, fmt.Errorf("only one %v can be included in the layout", t)
+		}
+	}
 
 	return tokens, nil
 }
 
+// quoteLiteral returns the literal text for a layout, quoted if the text would not be tokenized as is.
+func quoteLiteral(text string) string {
+	tokens, err := tokenizeLayout(text)
+	if err == nil {
+		s := ""
+		literal := true
+		for _, t := range tokens {
+			if _, ok := t.(tokenSep); !ok {
+				literal = false
+				break
+			}
+			s += t.String()
+		}
+		if literal && s == text {
+			return text
+		}
+	}
+	return "'" + strings.ReplaceAll(text, "'", "''") + "'"
+}
+
+// matchToken returns the available token that matches v, or the separator of v.
+func matchToken(v string, available []token) token {
+	for _, t := range available {
+		if t.token() == v {
+			return t
+		}
+	}
+	return newTokenSep(v)
+}
+
+func containsAny(layout, target []token) bool {
+	for _, t := range target {
+		if contains(layout, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// quarter returns the quarter of the year (1-4).
+func quarter(t time.Time) int {
+	return (int(t.Month())-1)/3 + 1
+}
+
 func lessThanOneContains(layout, target []token) bool {
 	contained := []token{}
 	for _, t := range layout {
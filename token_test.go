@@ -16,12 +16,24 @@ func TestTokenCal(t *testing.T) {
 		{tYYYY, "2002"},
 		{tYY, "2"},
 		{t0Y, "02"},
+		{tGGGG, "2002"},
+		{tGG, "2"},
+		{t0G, "02"},
+		{tQ, "1"},
+		{t0Q, "01"},
 		{tMM, "2"},
 		{t0M, "02"},
 		{tWW, "6"},
 		{t0W, "06"},
 		{tDD, "4"},
 		{t0D, "04"},
+		{tDDD, "35"},
+		{t0DDD, "035"},
+		{tHH, "0"},
+		{t0H, "00"},
+		{tMI, "0"},
+		{t0MI, "00"},
+		{tEPOCH, "1012780800"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.token.token(), func(t *testing.T) {
@@ -46,11 +58,20 @@ func TestTokentrimPrefix(t *testing.T) {
 		{tYYYY, "203", "", "", true},
 		{tYY, "3.12.05", "3", ".12.05", false},
 		{tYY, "12.12.05", "12", ".12.05", false},
+		{tDDD, "292.0", "292", ".0", false},
+		{tDDD, "7.0", "7", ".0", false},
+		{t0DDD, "007.0", "007", ".0", false},
+		{t0DDD, "07.0", "", "", true},
+		{tHH, "0.5", "0", ".5", false},
+		{tHH, "23.5", "23", ".5", false},
+		{t0H, "9.5", "", "", true},
+		{tEPOCH, "1729209600", "1729209600", "", false},
 		{t0Y, "03.12.05", "03", ".12.05", false},
 		{tMAJOR, "3.7.5", "3", ".7.5", false},
 		{tMINOR, "7.5", "7", ".5", false},
 		{tMICRO, "5-dev", "5", "-dev", false},
 		{tMODIFIER, "-dev", "-dev", "", false},
+		{tMETADATA, "build.5812.gabc123", "build.5812.gabc123", "", false},
 		{tMICRO, "dev", "", "", true},
 		{newTokenSep("."), ".5", ".", "5", false},
 		{newTokenSep("."), "3.5", "", "", true},
@@ -88,6 +109,34 @@ func TestTokenizeLayout(t *testing.T) {
 		{"YYY", []token{tYY, newTokenSep("Y")}, false},
 		{"YY.0Y", nil, true},
 		{"YYYY.YYYY", nil, true},
+		{"YYYY.0Q", []token{tYYYY, newTokenSep("."), t0Q}, false},
+		{"YYYY.QMICRO", []token{tYYYY, newTokenSep("."), tQ, tMICRO}, false},
+		{"Q.0Q", nil, true},
+		{"YYYY.Q.MM", nil, true},
+		{"YYYY.0Q.0W", nil, true},
+		{"YYYY.Q.0D", nil, true},
+		{"YYYY.0Q.DD", nil, true},
+		{"YYYY.Q.DDD", nil, true},
+		{"YY.DDD.MICRO", []token{tYY, newTokenSep("."), tDDD, newTokenSep("."), tMICRO}, false},
+		{"YY.0DDD", []token{tYY, newTokenSep("."), t0DDD}, false},
+		{"DDD.0DDD", nil, true},
+		{"YY.0M.0DDD", nil, true},
+		{"YY.DDD.DD", nil, true},
+		{"YY.0W.DDD", nil, true},
+		{"YYYY.0M.0D.HH0MI", []token{tYYYY, newTokenSep("."), t0M, newTokenSep("."), t0D, newTokenSep("."), tHH, t0MI}, false},
+		{"YYYY.0M.EPOCH", []token{tYYYY, newTokenSep("."), t0M, newTokenSep("."), tEPOCH}, false},
+		{"GGGG.0W", []token{tGGGG, newTokenSep("."), t0W}, false},
+		{"GG.0G", nil, true},
+		{"GGGG", nil, true},
+		{"GGGG.0M", nil, true},
+		{"GGGG.0M.0D", nil, true},
+		{"GGGG.DDD", nil, true},
+		{"GG.Q", nil, true},
+		{"YYYY.GGGG.0W", nil, true},
+		{"0G.0W.DD", nil, true},
+		{"HH.0H", nil, true},
+		{"MI.0MI", nil, true},
+		{"EPOCH.EPOCH", nil, true},
 		{"MM.0M", nil, true},
 		{"WW.0W", nil, true},
 		{"DD.0D", nil, true},
@@ -95,6 +144,15 @@ func TestTokenizeLayout(t *testing.T) {
 		{"MINOR.MINOR", nil, true},
 		{"MICRO.MICRO", nil, true},
 		{"MODIFIER.MODIFIER", nil, true},
+		{"MICRO-MODIFIER+METADATA", []token{tMICRO, newTokenSep("-"), tMODIFIER, newTokenSep("+"), tMETADATA}, false},
+		{"METADATA.METADATA", nil, true},
+		{"'ADD'-YYYY.0M", []token{newTokenLiteral("ADD", "'ADD'"), newTokenSep("-"), tYYYY, newTokenSep("."), t0M}, false},
+		{"\\A\\D\\D-YYYY", []token{newTokenLiteral("A", "\\A"), newTokenLiteral("D", "\\D"), newTokenLiteral("D", "\\D"), newTokenSep("-"), tYYYY}, false},
+		{"'it''s'YY", []token{newTokenLiteral("it's", "'it''s'"), tYY}, false},
+		{"''YY", []token{newTokenLiteral("'", "''"), tYY}, false},
+		{"YY.0M\\[MICRO\\]", []token{tYY, newTokenSep("."), t0M, newTokenLiteral("[", "\\["), tMICRO, newTokenLiteral("]", "\\]")}, false},
+		{"'MM.YY", nil, true},
+		{"YY\\", nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.layout, func(t *testing.T) {
@@ -119,3 +177,68 @@ func TestTokenizeLayout(t *testing.T) {
 		})
 	}
 }
+
+func TestLiteral(t *testing.T) {
+	tests := []struct {
+		layout string
+		value  string
+	}{
+		{"'ADD'-YYYY.0M", "ADD-2024.10"},
+		{"\\MM-YYYY.0M", "MM-2024.10"},
+		{"'MMX' YY.0M.MICRO", "MMX 24.10.1"},
+		{"'it''s'.YY.0M", "it's.24.10"},
+		{"YY.0M.MICRO-MODIFIER'+'METADATA", "24.10.1-rc.1+abc"},
+		{"YY.0M.MICRO-MODIFIER\\+METADATA", "24.10.1-rc.1+abc"},
+		{"YY.0M.MICRO-MODIFIER'MM'METADATA", "24.10.1-rc.1MMabc"},
+		{"YYYY.MM0D'-'MICRO", "2024.105-3"},
+		{"YYYY.MM0D\\-MICRO", "2024.1005-3"},
+		{"YYYY.DDD'.'MICRO", "2024.5.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			cv, err := Parse(tt.layout, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cv.String() != tt.value {
+				t.Errorf("got %v\nwant %v", cv.String(), tt.value)
+			}
+			if cv.Layout() != tt.layout {
+				t.Errorf("got %v\nwant %v", cv.Layout(), tt.layout)
+			}
+		})
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"", ""},
+		{".", "."},
+		{"v", "v"},
+		{"ADD-", "'ADD-'"},
+		{"it's", "'it''s'"},
+		{"[", "'['"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := quoteLiteral(tt.text)
+			if got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+			tokens, err := tokenizeLayout(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := ""
+			for _, tk := range tokens {
+				s += tk.String()
+			}
+			if s != tt.text {
+				t.Errorf("got %v\nwant %v", s, tt.text)
+			}
+		})
+	}
+}